@@ -9,21 +9,47 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/logging"
 	"github.com/ethersphere/node-funder/pkg/funder"
+	"github.com/ethersphere/node-funder/pkg/wallet"
 	"github.com/spf13/cobra"
 )
 
 const (
 	optionLogVerbosity string = "log-verbosity"
+
+	// envWalletPassword is environment variable with password of the wallet
+	// keystore, used when password file is not set.
+	envWalletPassword = "FUNDER_WALLET_PASSWORD"
+	// envWalletKey is environment variable with hex encoded key of the wallet,
+	// used when no other wallet key argument is set.
+	envWalletKey = "FUNDER_WALLET_KEY"
+	// envWalletMnemonic is environment variable with mnemonic of the wallet,
+	// used when no other wallet key argument is set.
+	envWalletMnemonic = "FUNDER_WALLET_MNEMONIC"
 )
 
 func main() {
 	cfg := funder.Config{}
 
-	var logLevel string
+	var (
+		logLevel    string
+		dryRun      bool
+		reportPath  string
+		concurrency int
+		metricsAddr string
+		watchPods   bool
+		stakeCfg    funder.Config
+		deriveCfg   funder.Config
+		deriveCount uint32
+		keyFile     string
+	)
 
 	rootCmd := &cobra.Command{
 		Use: "funder",
@@ -36,95 +62,405 @@ func main() {
 
 	rootCmd.PersistentFlags().StringVar(&logLevel, optionLogVerbosity, "info", "log verbosity level 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace")
 
-	logger, err := newLogger(rootCmd, logLevel)
+	logger, err := newLogger(rootCmd.OutOrStdout(), logLevel)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// logger is made again once flags are parsed, it logs to stderr when the
+	// report is written to stdout, so the report stays parsable
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		if reportPath == "-" {
+			out = cmd.ErrOrStderr()
+		}
+
+		l, err := newLogger(out, logLevel)
+		if err != nil {
+			return err
+		}
+
+		logger = l
+
+		return nil
+	}
+
 	fundCmd := &cobra.Command{
 		Use:   "fund",
 		Short: "fund (top up) bee node wallets",
 		Run: func(cmd *cobra.Command, args []string) {
-			doFund(cfg, logger)
+			cfg.WalletPassword = os.Getenv(envWalletPassword)
+			if cfg.WalletKey == "" && cfg.WalletKeyFile == "" && cfg.WalletKeystore == "" && cfg.WalletMnemonicFile == "" && cfg.WalletSigner == "" {
+				cfg.WalletMnemonic = os.Getenv(envWalletMnemonic)
+				if os.Getenv(envWalletKey) != "" {
+					cfg.WalletKeySource = wallet.EnvKeySource(envWalletKey)
+				}
+			}
+
+			options := []funder.FunderOptions{funder.WithLoggerOption(logger), funder.WithConcurrencyOption(concurrency)}
+			if metricsAddr != "" {
+				options = append(options, serveMetrics(metricsAddr, logger))
+			}
+
+			if watchPods {
+				if dryRun || cfg.Interval > 0 {
+					logger.Fatalf("--watchPods cannot be used with --dry-run or --interval")
+				}
+
+				doWatchNodes(cfg, stakeCfg, logger, options...)
+				return
+			}
+
+			if cfg.Interval > 0 {
+				if dryRun {
+					logger.Fatalf("--dry-run cannot be used with --interval")
+				}
+
+				doWatch(cmd.OutOrStdout(), cfg, reportPath, logger, options...)
+				return
+			}
+
+			if dryRun {
+				doPlan(cmd.OutOrStdout(), cfg, logger, options...)
+				return
+			}
+
+			doFund(cmd.OutOrStdout(), cfg, reportPath, logger, options...)
 		},
 	}
 
-	fundCmd.PersistentFlags().StringVar(&cfg.Namespace, "namespace", "", "kubernetes namespace")
+	fundCmd.PersistentFlags().StringSliceVar(&cfg.Namespaces, "namespace", nil, "kubernetes namespaces, comma separated or repeated")
+	fundCmd.PersistentFlags().BoolVar(&cfg.AllNamespaces, "all-namespaces", false, "fund nodes in all kubernetes namespaces, requires --selector")
+	fundCmd.PersistentFlags().StringVar(&cfg.LabelSelector, "selector", "", "label selector of bee pods in the namespace (e.g. app.kubernetes.io/name=bee); pods are selected by name containing \"bee\" when no selector is set")
+	fundCmd.PersistentFlags().StringVar(&cfg.FieldSelector, "field-selector", "", "field selector narrowing bee pods in the namespace (e.g. status.phase=Running), it does not replace --selector or the pod name filter")
+	fundCmd.PersistentFlags().IntVar(&cfg.APIPort, "apiPort", 0, "port of bee API of pods, overridden by the node-funder/api-port pod annotation (0 means the container port named \"api\", or 1633)")
+	fundCmd.PersistentFlags().StringVar(&cfg.APIScheme, "apiScheme", "http", "scheme of bee API of pods, http or https, overridden by the node-funder/api-scheme pod annotation")
+	fundCmd.PersistentFlags().StringVar(&cfg.APICACertFile, "apiCACert", "", "path to PEM bundle of CAs trusted by https bee API, in addition to system CAs")
+	fundCmd.PersistentFlags().StringVar(&cfg.APIToken, "apiToken", "", "bearer token of bee API")
+	fundCmd.PersistentFlags().StringVar(&cfg.APITokenFile, "apiTokenFile", "", "path to file with bearer token of bee API")
+	fundCmd.PersistentFlags().StringVar(&cfg.APITokenSecret, "apiTokenSecret", "", "name of kubernetes secret with bearer token of bee API under \"token\" key, read from namespace of each node")
+	fundCmd.PersistentFlags().StringVar(&cfg.APIUsername, "apiUsername", "", "basic auth username of bee API")
+	fundCmd.PersistentFlags().StringVar(&cfg.APIPassword, "apiPassword", "", "basic auth password of bee API")
+	fundCmd.PersistentFlags().DurationVar(&cfg.APITimeout, "apiTimeout", time.Minute, "how long a single bee API request may take")
+	fundCmd.PersistentFlags().IntVar(&cfg.APIRetries, "apiRetries", 3, "how many times bee API requests failing with server or connection errors are retried (0 means the default, negative means no retries)")
+	fundCmd.PersistentFlags().DurationVar(&cfg.ReadyTimeout, "readyTimeout", 0, "how long to wait for bee pods which are not running and ready, pods still not ready are skipped and reported (0 means no waiting)")
+	fundCmd.PersistentFlags().StringVar(&cfg.Kubeconfig, "kubeconfig", "", "path to kubeconfig file (defaults to KUBECONFIG or $HOME/.kube/config, and to in-cluster configuration when there is none)")
+	fundCmd.PersistentFlags().StringVar(&cfg.KubeContext, "kube-context", "", "kubeconfig context (defaults to the current context)")
+	fundCmd.PersistentFlags().StringVar(&cfg.Inventory, "inventory", "", "path to YAML or JSON inventory file listing names and bee API URLs of nodes outside of kubernetes; --namespace selects nodes of the namespace in the inventory")
+	fundCmd.PersistentFlags().BoolVar(&cfg.Docker, "docker", false, "list bee containers from docker instead of kubernetes; --namespace selects containers of the docker compose project")
+	fundCmd.PersistentFlags().StringVar(&cfg.DockerHost, "dockerHost", "", "docker engine API address (defaults to DOCKER_HOST or unix:///var/run/docker.sock)")
+	fundCmd.PersistentFlags().StringVar(&cfg.DockerName, "dockerName", "", "with --docker, selects bee containers by name (e.g. ^bee-); --selector selects them by label")
 	fundCmd.PersistentFlags().StringSliceVar(&cfg.Addresses, "addresses", nil, "wallet addresses")
 	fundCmd.PersistentFlags().StringVar(&cfg.ChainNodeEndpoint, "chainNodeEndpoint", "", "endpoint to chain node")
-	fundCmd.PersistentFlags().StringVar(&cfg.WalletKey, "walletKey", "", "wallet key")
-	fundCmd.PersistentFlags().Float64Var(&cfg.MinAmounts.NativeCoin, "minNative", 0, "specifies min amount of chain native coins (DAI) nodes should have")
-	fundCmd.PersistentFlags().Float64Var(&cfg.MinAmounts.SwarmToken, "minSwarm", 0, "specifies min amount of swarm tokens (BZZ) nodes should have")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletKey, "walletKey", "", "wallet key (visible in process list and shell history, prefer --walletKeyFile, --walletKeystore or "+envWalletKey+" environment variable)")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletKeyFile, "walletKeyFile", "", "path to file with hex encoded wallet key")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletKeystore, "walletKeystore", "", "path to go-ethereum V3 keystore file with encrypted wallet key; password is read from --walletPasswordFile or "+envWalletPassword+" environment variable")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletPasswordFile, "walletPasswordFile", "", "path to file with password of --walletKeystore")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletMnemonicFile, "walletMnemonicFile", "", "path to file with BIP-39 mnemonic the wallet key is derived from (defaults to "+envWalletMnemonic+" environment variable); its optional passphrase is read from --walletPasswordFile or "+envWalletPassword+" environment variable")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletDerivationPath, "walletDerivationPath", wallet.DefaultDerivationPath, "BIP-44 base derivation path of --walletMnemonicFile accounts")
+	fundCmd.PersistentFlags().Uint32Var(&cfg.WalletIndex, "walletIndex", 0, "index of --walletMnemonicFile account under --walletDerivationPath")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletSigner, "walletSigner", "", "JSON-RPC endpoint of a remote signer (e.g. Clef) signing transactions of the funding wallet, instead of a wallet key")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletSignerMethod, "walletSignerMethod", "eth_signTransaction", "sign method of --walletSigner, eth_signTransaction or account_signTransaction (Clef)")
+	fundCmd.PersistentFlags().StringVar(&cfg.WalletAddress, "walletAddress", "", "address of the funding wallet in --walletSigner (defaults to the only account of the signer)")
+	fundCmd.PersistentFlags().StringVar(&cfg.MinAmounts.NativeCoin, "minNative", "", "specifies min amount of chain native coins (DAI) nodes should have")
+	fundCmd.PersistentFlags().StringVar(&cfg.MinAmounts.SwarmToken, "minSwarm", "", "specifies min amount of swarm tokens (BZZ) nodes should have")
+	fundCmd.PersistentFlags().StringVar(&cfg.TargetAmounts.NativeCoin, "targetNative", "", "specifies amount of chain native coins (DAI) nodes below min amount are topped up to (defaults to min amount)")
+	fundCmd.PersistentFlags().StringVar(&cfg.TargetAmounts.SwarmToken, "targetSwarm", "", "specifies amount of swarm tokens (BZZ) nodes below min amount are topped up to (defaults to min amount)")
+	fundCmd.PersistentFlags().StringVar(&cfg.MaxTotal.NativeCoin, "maxTotalNative", "", "max amount of chain native coins (DAI) transferred to all nodes in a single run, or while running with --watchPods (0 means no limit)")
+	fundCmd.PersistentFlags().StringVar(&cfg.MaxTotal.SwarmToken, "maxTotalSwarm", "", "max amount of swarm tokens (BZZ) transferred to all nodes in a single run, or while running with --watchPods (0 means no limit)")
+	fundCmd.PersistentFlags().StringVar(&cfg.MaxPerWallet.NativeCoin, "maxPerWalletNative", "", "max amount of chain native coins (DAI) transferred to a single node (0 means no limit)")
+	fundCmd.PersistentFlags().StringVar(&cfg.MaxPerWallet.SwarmToken, "maxPerWalletSwarm", "", "max amount of swarm tokens (BZZ) transferred to a single node (0 means no limit)")
+	fundCmd.PersistentFlags().Uint64Var(&cfg.Confirmations, "confirmations", 0, "number of blocks mined on top of a transfer before it is considered done")
+	fundCmd.PersistentFlags().DurationVar(&cfg.ReceiptTimeout, "receiptTimeout", 5*time.Minute, "how long to wait for a transfer to be mined")
+	fundCmd.PersistentFlags().DurationVar(&cfg.Interval, "interval", 0, "keep running and fund wallets every interval (0 means fund once and exit)")
+	fundCmd.PersistentFlags().DurationVar(&cfg.ShutdownTimeout, "shutdownTimeout", time.Minute, "with --interval, how long transfers already sent may be mined after SIGINT or SIGTERM before they are abandoned")
+	fundCmd.PersistentFlags().BoolVar(&watchPods, "watchPods", false, "keep running and fund nodes in the namespace as soon as their pods become ready")
+	fundCmd.PersistentFlags().DurationVar(&cfg.WatchDebounce, "watchDebounce", 10*time.Second, "with --watchPods, how long pod events of a node have to settle before it is funded")
+	fundCmd.PersistentFlags().StringVar(&stakeCfg.MinAmounts.SwarmToken, "minStake", "", "with --watchPods, min amount of swarm tokens (BZZ) new nodes should have staked, nodes are not staked when empty")
+	fundCmd.PersistentFlags().StringVar(&stakeCfg.TargetAmounts.SwarmToken, "targetStake", "", "with --watchPods, amount of swarm tokens (BZZ) stake of new nodes below min stake is topped up to (defaults to min stake)")
+	fundCmd.PersistentFlags().BoolVar(&cfg.PrioritizedFunding, "prioritize", false, "when the funding wallet cannot cover all top-ups, fund wallets with the lowest balance first instead of aborting")
+	fundCmd.PersistentFlags().StringVar(&reportPath, "report", "", "write JSON report of the funding run to the file path, or to stdout when set to -")
+	fundCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 10, "max number of nodes processed at once (0 means no limit)")
+	fundCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", "", "serve prometheus metrics on /metrics endpoint of the address (e.g. :9090), disabled when empty")
+	fundCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the funding plan without transferring anything; exits non-zero if the funding wallet cannot cover it")
 
 	stakeCmd := &cobra.Command{
 		Use:   "stake",
 		Short: "stake (top up) bee nodes",
 		Run: func(cmd *cobra.Command, args []string) {
-			doStake(cfg, logger)
+			cfg.WalletPassword = os.Getenv(envWalletPassword)
+
+			options := []funder.FunderOptions{funder.WithLoggerOption(logger), funder.WithConcurrencyOption(concurrency)}
+			if metricsAddr != "" {
+				options = append(options, serveMetrics(metricsAddr, logger))
+			}
+
+			doStake(cfg, logger, options...)
+		},
+	}
+	stakeCmd.PersistentFlags().StringSliceVar(&cfg.Namespaces, "namespace", nil, "kubernetes namespaces, comma separated or repeated")
+	stakeCmd.PersistentFlags().BoolVar(&cfg.AllNamespaces, "all-namespaces", false, "stake nodes in all kubernetes namespaces, requires --selector")
+	stakeCmd.PersistentFlags().StringVar(&cfg.LabelSelector, "selector", "", "label selector of bee pods in the namespace (e.g. app.kubernetes.io/name=bee); pods are selected by name containing \"bee\" when no selector is set")
+	stakeCmd.PersistentFlags().StringVar(&cfg.FieldSelector, "field-selector", "", "field selector narrowing bee pods in the namespace (e.g. status.phase=Running), it does not replace --selector or the pod name filter")
+	stakeCmd.PersistentFlags().IntVar(&cfg.APIPort, "apiPort", 0, "port of bee API of pods, overridden by the node-funder/api-port pod annotation (0 means the container port named \"api\", or 1633)")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APIScheme, "apiScheme", "http", "scheme of bee API of pods, http or https, overridden by the node-funder/api-scheme pod annotation")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APICACertFile, "apiCACert", "", "path to PEM bundle of CAs trusted by https bee API, in addition to system CAs")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APIToken, "apiToken", "", "bearer token of bee API")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APITokenFile, "apiTokenFile", "", "path to file with bearer token of bee API")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APITokenSecret, "apiTokenSecret", "", "name of kubernetes secret with bearer token of bee API under \"token\" key, read from namespace of each node")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APIUsername, "apiUsername", "", "basic auth username of bee API")
+	stakeCmd.PersistentFlags().StringVar(&cfg.APIPassword, "apiPassword", "", "basic auth password of bee API")
+	stakeCmd.PersistentFlags().DurationVar(&cfg.APITimeout, "apiTimeout", time.Minute, "how long a single bee API request may take")
+	stakeCmd.PersistentFlags().IntVar(&cfg.APIRetries, "apiRetries", 3, "how many times bee API requests failing with server or connection errors are retried (0 means the default, negative means no retries)")
+	stakeCmd.PersistentFlags().DurationVar(&cfg.ReadyTimeout, "readyTimeout", 0, "how long to wait for bee pods which are not running and ready, pods still not ready are skipped and reported (0 means no waiting)")
+	stakeCmd.PersistentFlags().StringVar(&cfg.Kubeconfig, "kubeconfig", "", "path to kubeconfig file (defaults to KUBECONFIG or $HOME/.kube/config, and to in-cluster configuration when there is none)")
+	stakeCmd.PersistentFlags().StringVar(&cfg.KubeContext, "kube-context", "", "kubeconfig context (defaults to the current context)")
+	stakeCmd.PersistentFlags().StringVar(&cfg.Inventory, "inventory", "", "path to YAML or JSON inventory file listing names and bee API URLs of nodes outside of kubernetes; --namespace selects nodes of the namespace in the inventory")
+	stakeCmd.PersistentFlags().BoolVar(&cfg.Docker, "docker", false, "list bee containers from docker instead of kubernetes; --namespace selects containers of the docker compose project")
+	stakeCmd.PersistentFlags().StringVar(&cfg.DockerHost, "dockerHost", "", "docker engine API address (defaults to DOCKER_HOST or unix:///var/run/docker.sock)")
+	stakeCmd.PersistentFlags().StringVar(&cfg.DockerName, "dockerName", "", "with --docker, selects bee containers by name (e.g. ^bee-); --selector selects them by label")
+	stakeCmd.PersistentFlags().StringVar(&cfg.MinAmounts.SwarmToken, "minSwarm", "", "specifies min amount of swarm tokens (BZZ) nodes should have staked")
+	stakeCmd.PersistentFlags().StringVar(&cfg.TargetAmounts.SwarmToken, "targetSwarm", "", "specifies amount of swarm tokens (BZZ) stake of nodes below min amount is topped up to (defaults to min amount)")
+	stakeCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 10, "max number of nodes processed at once (0 means no limit)")
+	stakeCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", "", "serve prometheus metrics on /metrics endpoint of the address (e.g. :9090), disabled when empty")
+	stakeCmd.PersistentFlags().BoolVar(&cfg.AllowPartialStake, "allow-partial", false, "exit successfully when staking of some, but not all, nodes fails")
+
+	deriveCmd := &cobra.Command{
+		Use:   "derive",
+		Short: "print addresses of wallets derived from a mnemonic, one per line",
+		Run: func(cmd *cobra.Command, args []string) {
+			deriveCfg.WalletPassword = os.Getenv(envWalletPassword)
+			if deriveCfg.WalletMnemonicFile == "" {
+				deriveCfg.WalletMnemonic = os.Getenv(envWalletMnemonic)
+			}
+
+			doDerive(cmd.OutOrStdout(), deriveCfg, deriveCount, logger)
+		},
+	}
+	deriveCmd.PersistentFlags().StringVar(&deriveCfg.WalletMnemonicFile, "walletMnemonicFile", "", "path to file with BIP-39 mnemonic (defaults to "+envWalletMnemonic+" environment variable); its optional passphrase is read from --walletPasswordFile or "+envWalletPassword+" environment variable")
+	deriveCmd.PersistentFlags().StringVar(&deriveCfg.WalletPasswordFile, "walletPasswordFile", "", "path to file with passphrase of --walletMnemonicFile")
+	deriveCmd.PersistentFlags().StringVar(&deriveCfg.WalletDerivationPath, "walletDerivationPath", wallet.DefaultDerivationPath, "BIP-44 base derivation path of accounts")
+	deriveCmd.PersistentFlags().Uint32Var(&deriveCfg.WalletIndex, "walletIndex", 0, "index of the first account")
+	deriveCmd.PersistentFlags().Uint32Var(&deriveCount, "count", 1, "number of accounts")
+
+	keygenCmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "generate a new wallet key and print its address, e.g. to bootstrap funding wallet of a local cluster",
+		Run: func(cmd *cobra.Command, args []string) {
+			doKeygen(cmd.OutOrStdout(), keyFile, logger)
 		},
 	}
-	stakeCmd.PersistentFlags().StringVar(&cfg.Namespace, "namespace", "", "kubernetes namespace")
-	stakeCmd.PersistentFlags().Float64Var(&cfg.MinAmounts.SwarmToken, "minSwarm", 0, "specifies min amount of swarm tokens (BZZ) nodes should have staked")
+	keygenCmd.PersistentFlags().StringVar(&keyFile, "keyFile", "", "write hex encoded key to the file, usable as --walletKeyFile, instead of printing it")
 
-	rootCmd.AddCommand(fundCmd, stakeCmd)
+	rootCmd.AddCommand(fundCmd, stakeCmd, deriveCmd, keygenCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.Fatal(err)
 	}
 }
 
-func doFund(cfg funder.Config, logger logging.Logger) {
+func doFund(out io.Writer, cfg funder.Config, reportPath string, logger logging.Logger, options ...funder.FunderOptions) {
 	ctx := context.Background()
 
-	if cfg.Namespace == "" && len(cfg.Addresses) == 0 {
-		logger.Fatalf("--namespace or --addresses must be set")
-		return
+	validateFundConfig(cfg, logger)
+
+	report, fundErr := funder.Fund(ctx, cfg, nil, nil, options...)
+
+	if reportPath != "" {
+		if err := writeReport(out, reportPath, report); err != nil {
+			logger.Errorf("error while writing report: %v", err)
+		}
+	}
+
+	if fundErr != nil {
+		logger.Fatalf("error while funding: %v", fundErr)
+	}
+}
+
+func doWatch(out io.Writer, cfg funder.Config, reportPath string, logger logging.Logger, options ...funder.FunderOptions) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	validateFundConfig(cfg, logger)
+
+	err := funder.Watch(ctx, cfg, nil, nil, func(report funder.FundReport, _ error) {
+		if reportPath == "" {
+			return
+		}
+
+		if err := writeReport(out, reportPath, report); err != nil {
+			logger.Errorf("error while writing report: %v", err)
+		}
+	}, options...)
+	if err != nil {
+		logger.Fatalf("error while watching: %v", err)
+	}
+}
+
+func doWatchNodes(cfg, stakeCfg funder.Config, logger logging.Logger, options ...funder.FunderOptions) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	validateFundConfig(cfg, logger)
+
+	if len(cfg.Namespaces) == 0 && !cfg.AllNamespaces {
+		logger.Fatalf("--namespace or --all-namespaces must be set with --watchPods")
+	}
+
+	if cfg.Inventory != "" || cfg.Docker {
+		logger.Fatalf("--watchPods cannot be used with --inventory or --docker")
+	}
+
+	var stake *funder.Config
+	if stakeCfg.MinAmounts.SwarmToken != "" {
+		stake = &stakeCfg
 	}
 
+	if err := funder.WatchNodes(ctx, cfg, stake, nil, nil, options...); err != nil {
+		logger.Fatalf("error while watching nodes: %v", err)
+	}
+}
+
+func writeReport(out io.Writer, path string, report funder.FundReport) (err error) {
+	if path == "-" {
+		return report.WriteJSON(out)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return report.WriteJSON(f)
+}
+
+func doPlan(out io.Writer, cfg funder.Config, logger logging.Logger, options ...funder.FunderOptions) {
+	ctx := context.Background()
+
+	validateFundConfig(cfg, logger)
+
+	plan, err := funder.Plan(ctx, cfg, nil, nil, options...)
+	if err != nil {
+		logger.Fatalf("error while planning funding: %v", err)
+	}
+
+	if err := plan.WriteTable(out); err != nil {
+		logger.Fatalf("error while printing funding plan: %v", err)
+	}
+
+	if !plan.Covered() {
+		logger.Fatalf("funding wallet cannot cover the funding plan")
+	}
+}
+
+func doDerive(out io.Writer, cfg funder.Config, count uint32, logger logging.Logger) {
+	if cfg.WalletMnemonicFile == "" && cfg.WalletMnemonic == "" {
+		logger.Fatalf("--walletMnemonicFile or %s must be set", envWalletMnemonic)
+	}
+
+	addresses, err := funder.DeriveAddresses(cfg, count)
+	if err != nil {
+		logger.Fatalf("error while deriving addresses: %v", err)
+	}
+
+	for _, address := range addresses {
+		if _, err := fmt.Fprintln(out, address.Hex()); err != nil {
+			logger.Fatalf("error while printing addresses: %v", err)
+		}
+	}
+}
+
+func doKeygen(out io.Writer, keyFile string, logger logging.Logger) {
+	key, err := wallet.GeneratedKeySource().Key()
+	if err != nil {
+		logger.Fatalf("error while generating key: %v", err)
+	}
+
+	address, err := key.PublicAddress()
+	if err != nil {
+		logger.Fatalf("error while generating key: %v", err)
+	}
+
+	if keyFile != "" {
+		if err := os.WriteFile(keyFile, []byte(string(key)+"\n"), 0o600); err != nil {
+			logger.Fatalf("error while writing key file: %v", err)
+		}
+	} else if _, err := fmt.Fprintf(out, "key: %s\n", key); err != nil {
+		logger.Fatalf("error while printing key: %v", err)
+	}
+
+	if _, err := fmt.Fprintf(out, "address: %s\n", address.Hex()); err != nil {
+		logger.Fatalf("error while printing address: %v", err)
+	}
+}
+
+func validateFundConfig(cfg funder.Config, logger logging.Logger) {
+	if len(cfg.Namespaces) == 0 && !cfg.AllNamespaces && cfg.Inventory == "" && !cfg.Docker && len(cfg.Addresses) == 0 {
+		logger.Fatalf("--namespace, --all-namespaces, --inventory, --docker or --addresses must be set")
+	}
+
+	if cfg.Inventory != "" && len(cfg.Addresses) > 0 {
+		logger.Fatalf("--inventory cannot be used with --addresses")
+	}
+
+	validateSelector(cfg, logger)
+
 	if cfg.ChainNodeEndpoint == "" {
 		logger.Fatalf("--chainNodeEndpoint must be set")
-		return
 	}
 
-	if cfg.WalletKey == "" {
-		logger.Fatalf("--walletKey must be set")
-		return
+	if cfg.WalletKey == "" && cfg.WalletKeyFile == "" && cfg.WalletKeySource == nil && cfg.WalletKeystore == "" && cfg.WalletMnemonicFile == "" && cfg.WalletMnemonic == "" && cfg.WalletSigner == "" {
+		logger.Fatalf("--walletKey, --walletKeyFile, --walletKeystore, --walletMnemonicFile, --walletSigner or %s must be set", envWalletKey)
+	}
+}
+
+func validateSelector(cfg funder.Config, logger logging.Logger) {
+	if cfg.AllNamespaces && cfg.Inventory == "" && !cfg.Docker && cfg.LabelSelector == "" {
+		logger.Fatalf("--selector must be set with --all-namespaces")
 	}
 
-	if err := funder.Fund(ctx, cfg, nil, nil); err != nil {
-		logger.Fatalf("error while funding: %v", err)
+	if cfg.Docker && cfg.FieldSelector != "" {
+		logger.Fatalf("--field-selector cannot be used with --docker")
 	}
 }
 
-func doStake(cfg funder.Config, logger logging.Logger) {
+func doStake(cfg funder.Config, logger logging.Logger, options ...funder.FunderOptions) {
 	ctx := context.Background()
 
-	if cfg.Namespace == "" {
-		logger.Fatalf("--namespace must be set")
+	if len(cfg.Namespaces) == 0 && !cfg.AllNamespaces && cfg.Inventory == "" && !cfg.Docker {
+		logger.Fatalf("--namespace, --all-namespaces, --inventory or --docker must be set")
 		return
 	}
 
-	if err := funder.Stake(ctx, cfg, nil); err != nil {
-		logger.Fatalf("error while funding: %v", err)
+	validateSelector(cfg, logger)
+
+	if _, err := funder.Stake(ctx, cfg, nil, options...); err != nil {
+		logger.Fatalf("error while staking: %v", err)
 	}
 }
 
-func newLogger(cmd *cobra.Command, verbosity string) (logging.Logger, error) {
+func newLogger(out io.Writer, verbosity string) (logging.Logger, error) {
 	var logger logging.Logger
 
 	switch strings.ToLower(verbosity) {
 	case "0", "silent":
 		logger = logging.New(io.Discard, 0)
 	case "1", "error":
-		logger = logging.New(cmd.OutOrStdout(), 2)
+		logger = logging.New(out, 2)
 	case "2", "warn":
-		logger = logging.New(cmd.OutOrStdout(), 3)
+		logger = logging.New(out, 3)
 	case "3", "info":
-		logger = logging.New(cmd.OutOrStdout(), 4)
+		logger = logging.New(out, 4)
 	case "4", "debug":
-		logger = logging.New(cmd.OutOrStdout(), 5)
+		logger = logging.New(out, 5)
 	case "5", "trace":
-		logger = logging.New(cmd.OutOrStdout(), 6)
+		logger = logging.New(out, 6)
 	default:
 		return nil, fmt.Errorf("unknown %s level %q, use help to check flag usage options", optionLogVerbosity, verbosity)
 	}
@@ -0,0 +1,336 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package beeclient is client of bee node API.
+package beeclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/bigint"
+)
+
+const (
+	defaultTimeout = time.Minute
+	defaultRetries = 3
+	defaultBackoff = 500 * time.Millisecond
+	maxBackoff     = 10 * time.Second
+	// defaultStakeTimeout is longer than defaultTimeout, as bee responds to
+	// stake deposits once their transaction is mined.
+	defaultStakeTimeout = 10 * time.Minute
+
+	// maxErrorBodySize limits error response body included in errors.
+	maxErrorBodySize = 4 * 1024
+)
+
+// Authorizer sets credentials of requests.
+type Authorizer interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// AuthorizerFunc is function implementing Authorizer.
+type AuthorizerFunc func(ctx context.Context, req *http.Request) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+type ClientOptions func(*Options)
+
+// Options represents client options
+type Options struct {
+	httpClient   *http.Client
+	timeout      time.Duration
+	stakeTimeout time.Duration
+	retries      int
+	backoff      time.Duration
+	authorizer   Authorizer
+}
+
+// DefaultOptions returns default options
+func DefaultOptions() *Options {
+	return &Options{
+		httpClient:   http.DefaultClient,
+		timeout:      defaultTimeout,
+		stakeTimeout: defaultStakeTimeout,
+		retries:      defaultRetries,
+		backoff:      defaultBackoff,
+	}
+}
+
+// WithHTTPClientOption sets HTTP client shared by clients of many nodes
+func WithHTTPClientOption(c *http.Client) ClientOptions {
+	return func(o *Options) {
+		if c != nil {
+			o.httpClient = c
+		}
+	}
+}
+
+// WithTimeoutOption sets how long a single request attempt may take
+func WithTimeoutOption(timeout time.Duration) ClientOptions {
+	return func(o *Options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithStakeTimeoutOption sets how long a stake deposit may take, including
+// mining of its transaction
+func WithStakeTimeoutOption(timeout time.Duration) ClientOptions {
+	return func(o *Options) {
+		if timeout > 0 {
+			o.stakeTimeout = timeout
+		}
+	}
+}
+
+// WithRetriesOption sets how many times failed requests are retried, zero
+// keeps the default and negative value turns retries off
+func WithRetriesOption(retries int) ClientOptions {
+	return func(o *Options) {
+		switch {
+		case retries > 0:
+			o.retries = retries
+		case retries < 0:
+			o.retries = 0
+		}
+	}
+}
+
+// WithBackoffOption sets delay before the first retry, it doubles with every
+// next retry
+func WithBackoffOption(backoff time.Duration) ClientOptions {
+	return func(o *Options) {
+		if backoff > 0 {
+			o.backoff = backoff
+		}
+	}
+}
+
+// WithAuthorizerOption sets credentials of requests, nil means none
+func WithAuthorizerOption(a Authorizer) ClientOptions {
+	return func(o *Options) {
+		o.authorizer = a
+	}
+}
+
+// Client is client of API of a single bee node.
+type Client struct {
+	baseURL string
+	opts    *Options
+}
+
+// New returns client of bee node API at the base URL (e.g. http://10.0.0.1:1633).
+func New(baseURL string, options ...ClientOptions) *Client {
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		opts:    opts,
+	}
+}
+
+// APIError is returned when bee API responds with non-success status.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // message of bee error response, or the raw body
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// Wallet is response of the /wallet endpoint.
+type Wallet struct {
+	WalletAddress string `json:"walletAddress"`
+	ChainID       int64  `json:"chainID"`
+}
+
+// Wallet returns wallet of the node.
+func (c *Client) Wallet(ctx context.Context) (Wallet, error) {
+	var w Wallet
+	if err := c.request(ctx, http.MethodGet, "/wallet", true, c.opts.timeout, &w); err != nil {
+		return Wallet{}, err
+	}
+
+	return w, nil
+}
+
+// Addresses is response of the /addresses endpoint.
+type Addresses struct {
+	Overlay   string `json:"overlay"`
+	Ethereum  string `json:"ethereum"`
+	PublicKey string `json:"publicKey"`
+}
+
+// Addresses returns addresses of the node.
+func (c *Client) Addresses(ctx context.Context) (Addresses, error) {
+	var a Addresses
+	if err := c.request(ctx, http.MethodGet, "/addresses", true, c.opts.timeout, &a); err != nil {
+		return Addresses{}, err
+	}
+
+	return a, nil
+}
+
+// Stake returns amount staked by the node.
+func (c *Client) Stake(ctx context.Context) (*big.Int, error) {
+	var resp struct {
+		StakedAmount *bigint.BigInt `json:"stakedAmount"`
+	}
+	if err := c.request(ctx, http.MethodGet, "/stake", true, c.opts.timeout, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StakedAmount == nil {
+		return big.NewInt(0), nil
+	}
+
+	return resp.StakedAmount.Int, nil
+}
+
+// DepositStake adds the amount to stake of the node. Bee responds once the
+// deposit transaction is mined, so the request may take up to the stake
+// timeout instead of the request timeout. As deposits add up, the request is
+// not retried, unless it could not be sent to the node at all.
+func (c *Client) DepositStake(ctx context.Context, amount *big.Int) error {
+	return c.request(ctx, http.MethodPost, "/stake/"+amount.String(), false, c.opts.stakeTimeout, nil)
+}
+
+// request sends request to the node, each attempt limited by the timeout,
+// decoding JSON response into result when it is not nil. Idempotent requests
+// are retried also on server errors and errors receiving the response.
+func (c *Client) request(ctx context.Context, method, path string, idempotent bool, timeout time.Duration, result any) error {
+	backoff := c.opts.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, method, path, timeout, result)
+		if err == nil || attempt >= c.opts.retries || !retryable(ctx, err, idempotent) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, timeout time.Duration, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.opts.authorizer != nil {
+		if err := c.opts.authorizer.Authorize(ctx, req); err != nil {
+			return fmt.Errorf("authorize request: %w", err)
+		}
+	}
+
+	resp, err := c.opts.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newAPIError(method, path, resp)
+	}
+
+	if result == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("%s %s: decode response: %w", method, path, err)
+	}
+
+	return nil
+}
+
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	apiErr := &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	var errResp struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Message != "" {
+		apiErr.Message = errResp.Message
+	}
+
+	return apiErr
+}
+
+// retryable reports whether the failed request should be retried.
+func retryable(ctx context.Context, err error, idempotent bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return idempotent && apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+
+	// the request did not reach the node
+	var opErr *net.OpError
+	if errors.As(urlErr.Err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	if !idempotent {
+		return false
+	}
+
+	var netErr net.Error
+
+	return urlErr.Timeout() ||
+		errors.As(urlErr.Err, &netErr) ||
+		errors.Is(urlErr.Err, io.EOF) ||
+		errors.Is(urlErr.Err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package beeclient_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethersphere/node-funder/pkg/beeclient"
+)
+
+func Test_Client(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("typed responses", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+			var body string
+			switch req.Method + " " + req.URL.Path {
+			case "GET /wallet":
+				body = `{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`
+			case "GET /addresses":
+				body = `{"ethereum": "0x95f8916183f7C7154e49396507F5b0FafA4d8077"}`
+			case "GET /stake":
+				body = `{"stakedAmount": "100000000000000000"}`
+			case "POST /stake/5":
+				body = `{"txHash": "0x00"}`
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, err := w.Write([]byte(body))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		c := beeclient.New(server.URL, beeclient.WithAuthorizerOption(beeclient.AuthorizerFunc(func(_ context.Context, req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer token")
+			return nil
+		})))
+
+		wallet, err := c.Wallet(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, beeclient.Wallet{WalletAddress: "0x95f8916183f7C7154e49396507F5b0FafA4d8077", ChainID: 100}, wallet)
+
+		addresses, err := c.Addresses(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x95f8916183f7C7154e49396507F5b0FafA4d8077", addresses.Ethereum)
+
+		staked, err := c.Stake(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "100000000000000000", staked.String())
+
+		assert.NoError(t, c.DepositStake(ctx, big.NewInt(5)))
+	})
+
+	t.Run("error body", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, err := w.Write([]byte(`{"code": 400, "message": "insufficient stake amount"}`))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		err := beeclient.New(server.URL).DepositStake(ctx, big.NewInt(1))
+
+		var apiErr *beeclient.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.EqualError(t, err, "POST /stake/1: 400 Bad Request: insufficient stake amount")
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name      string
+			status    int
+			retries   int
+			deposit   bool
+			wantCalls int32
+		}{
+			{name: "server error retried", status: http.StatusServiceUnavailable, retries: 2, wantCalls: 3},
+			{name: "client error not retried", status: http.StatusBadRequest, retries: 2, wantCalls: 1},
+			{name: "deposit not retried", status: http.StatusInternalServerError, retries: 2, deposit: true, wantCalls: 1},
+			{name: "retries turned off", status: http.StatusServiceUnavailable, retries: -1, wantCalls: 1},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var calls atomic.Int32
+
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					calls.Add(1)
+					w.WriteHeader(tc.status)
+				}))
+				t.Cleanup(server.Close)
+
+				c := beeclient.New(server.URL, beeclient.WithRetriesOption(tc.retries), beeclient.WithBackoffOption(time.Millisecond))
+
+				var err error
+				if tc.deposit {
+					err = c.DepositStake(ctx, big.NewInt(1))
+				} else {
+					_, err = c.Stake(ctx)
+				}
+
+				assert.Error(t, err)
+				assert.Equal(t, tc.wantCalls, calls.Load())
+			})
+		}
+
+		t.Run("recovered", func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if calls.Add(1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				_, err := w.Write([]byte(`{"stakedAmount": "1"}`))
+				assert.NoError(t, err)
+			}))
+			t.Cleanup(server.Close)
+
+			c := beeclient.New(server.URL, beeclient.WithBackoffOption(time.Millisecond))
+			staked, err := c.Stake(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, "1", staked.String())
+		})
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			<-req.Context().Done()
+		}))
+		t.Cleanup(server.Close)
+
+		c := beeclient.New(server.URL, beeclient.WithTimeoutOption(10*time.Millisecond), beeclient.WithBackoffOption(time.Millisecond))
+		_, err := c.Wallet(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("stake deposit timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// deposit responds once its transaction is mined
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		}))
+		t.Cleanup(server.Close)
+
+		c := beeclient.New(server.URL, beeclient.WithTimeoutOption(10*time.Millisecond), beeclient.WithRetriesOption(1), beeclient.WithBackoffOption(time.Millisecond))
+		assert.NoError(t, c.DepositStake(ctx, big.NewInt(1)))
+
+		c = beeclient.New(server.URL, beeclient.WithStakeTimeoutOption(10*time.Millisecond), beeclient.WithRetriesOption(1), beeclient.WithBackoffOption(time.Millisecond))
+		assert.ErrorIs(t, c.DepositStake(ctx, big.NewInt(1)), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+// parseAmount parses a decimal token amount (e.g. "0.1") or an integer amount
+// in token base units suffixed with the base unit name (e.g. "1000wei") into
+// an exact amount in base units. Empty value is parsed as zero.
+func parseAmount(value string, token wallet.Token) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+
+	if token.BaseUnit != "" && strings.HasSuffix(value, token.BaseUnit) {
+		digits := strings.TrimSpace(strings.TrimSuffix(value, token.BaseUnit))
+		if !isDigits(digits) {
+			return nil, fmt.Errorf("invalid amount %q: expected integer number of %s", value, token.BaseUnit)
+		}
+
+		amount, _ := new(big.Int).SetString(digits, 10)
+
+		return amount, nil
+	}
+
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if intPart == "" && fracPart == "" || intPart != "" && !isDigits(intPart) || fracPart != "" && !isDigits(fracPart) {
+		return nil, fmt.Errorf("invalid amount %q: expected decimal number or integer number suffixed with %q", value, token.BaseUnit)
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > token.Decimals {
+		return nil, fmt.Errorf("invalid amount %q: more than %d decimal places", value, token.Decimals)
+	}
+
+	amount, _ := new(big.Int).SetString(intPart+fracPart+strings.Repeat("0", token.Decimals-len(fracPart)), 10)
+
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatAmount formats amount in base units as exact decimal token amount.
+func formatAmount(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(amount).String()
+	if decimals <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	intPart := digits[:len(digits)-decimals]
+	fracPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	if fracPart == "" {
+		return sign + intPart
+	}
+
+	return sign + intPart + "." + fracPart
+}
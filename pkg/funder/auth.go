@@ -0,0 +1,158 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// apiTokenSecretKey is key of the bearer token in the Secret data.
+const apiTokenSecretKey = "token"
+
+var (
+	// ErrConflictingAPIAuth is returned when more than one bee API credential
+	// is configured.
+	ErrConflictingAPIAuth = errors.New("only one of api token, token file, token secret or basic auth can be set")
+	// ErrAPIUsernameRequired is returned when basic auth password of bee API
+	// is configured without username.
+	ErrAPIUsernameRequired = errors.New("api username is required with api password")
+)
+
+// apiAuth authenticates bee API requests with a bearer token or basic auth.
+type apiAuth struct {
+	token    string // static bearer token
+	username string
+	password string
+
+	// bearer token read from the Secret in namespace of the node
+	secret  string
+	secrets corev1client.SecretsGetter
+	mu      sync.Mutex
+	tokens  map[string]string // tokens read from secrets by namespace
+}
+
+// newAPIAuth returns authentication configured in cfg, or nil when no
+// credentials are configured.
+func newAPIAuth(cfg Config) (*apiAuth, error) {
+	if cfg.APIPassword != "" && cfg.APIUsername == "" {
+		return nil, ErrAPIUsernameRequired
+	}
+
+	var set int
+
+	for _, v := range []string{cfg.APIToken, cfg.APITokenFile, cfg.APITokenSecret, cfg.APIUsername} {
+		if v != "" {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return nil, ErrConflictingAPIAuth
+	}
+
+	switch {
+	case cfg.APIToken != "":
+		return &apiAuth{token: cfg.APIToken}, nil
+	case cfg.APITokenFile != "":
+		data, err := os.ReadFile(cfg.APITokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read api token file: %w", err)
+		}
+
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return nil, fmt.Errorf("api token file %s is empty", cfg.APITokenFile)
+		}
+
+		return &apiAuth{token: token}, nil
+	case cfg.APITokenSecret != "":
+		client, err := newKube(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return newSecretAPIAuth(cfg.APITokenSecret, client), nil
+	case cfg.APIUsername != "":
+		return &apiAuth{username: cfg.APIUsername, password: cfg.APIPassword}, nil
+	}
+
+	return nil, nil
+}
+
+func newSecretAPIAuth(secret string, secrets corev1client.SecretsGetter) *apiAuth {
+	return &apiAuth{
+		secret:  secret,
+		secrets: secrets,
+		tokens:  make(map[string]string),
+	}
+}
+
+// authorize sets credentials of the request to node in the namespace.
+func (a *apiAuth) authorize(ctx context.Context, req *http.Request, namespace string) error {
+	if a == nil {
+		return nil
+	}
+
+	if a.username != "" {
+		req.SetBasicAuth(a.username, a.password)
+		return nil
+	}
+
+	token := a.token
+	if a.secret != "" {
+		var err error
+
+		token, err = a.secretToken(ctx, namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// secretToken returns bearer token from the Secret in the namespace. Tokens
+// are cached per namespace once read; the Secret is read without holding the
+// lock, so a slow API server does not block requests to other namespaces.
+func (a *apiAuth) secretToken(ctx context.Context, namespace string) (string, error) {
+	a.mu.Lock()
+	token, ok := a.tokens[namespace]
+	a.mu.Unlock()
+
+	if ok {
+		return token, nil
+	}
+
+	if namespace == "" {
+		return "", fmt.Errorf("api token secret %s: namespace of the node is unknown", a.secret)
+	}
+
+	secret, err := a.secrets.Secrets(namespace).Get(ctx, a.secret, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("get api token secret %s/%s: %w", namespace, a.secret, err)
+	}
+
+	token = strings.TrimSpace(string(secret.Data[apiTokenSecretKey]))
+	if token == "" {
+		return "", fmt.Errorf("api token secret %s/%s has no %q key", namespace, a.secret, apiTokenSecretKey)
+	}
+
+	a.mu.Lock()
+	a.tokens[namespace] = token
+	a.mu.Unlock()
+
+	return token, nil
+}
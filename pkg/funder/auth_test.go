@@ -0,0 +1,173 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	fundermock "github.com/ethersphere/node-funder/pkg/funder/mock"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+func Test_APIAuth(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := generateKey(t)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+
+	// server responds only to requests authenticated with bearer token
+	// "secret" or basic auth "bee:pass"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, pass, ok := req.BasicAuth()
+		if req.Header.Get("Authorization") != "Bearer secret" && (!ok || user != "bee" || pass != "pass") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL, Name: "bee-0"}})
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		failed bool
+	}{
+		{name: "no credentials", failed: true},
+		{name: "invalid token", cfg: Config{APIToken: "invalid"}, failed: true},
+		{name: "token", cfg: Config{APIToken: "secret"}},
+		{name: "token file", cfg: Config{APITokenFile: tokenFile}},
+		{name: "basic auth", cfg: Config{APIUsername: "bee", APIPassword: "pass"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.Namespace = "swarm"
+			report, err := Fund(ctx, tc.cfg, nl, w)
+			assert.Equal(t, 1, report.Totals.Wallets)
+
+			if tc.failed {
+				assert.Error(t, err)
+				assert.Equal(t, 1, report.Totals.Failed)
+				assert.ErrorIs(t, report.Wallets[0].Err, ErrFailedFetchingWallet)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("conflicting credentials", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Namespace: "swarm", APIToken: "secret", APIUsername: "bee"}
+		_, err := Fund(ctx, cfg, nl, w)
+		assert.ErrorIs(t, err, ErrConflictingAPIAuth)
+	})
+
+	t.Run("password without username", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Namespace: "swarm", APIPassword: "pass"}
+		_, err := Fund(ctx, cfg, nl, w)
+		assert.ErrorIs(t, err, ErrAPIUsernameRequired)
+	})
+
+	t.Run("token secret", func(t *testing.T) {
+		t.Parallel()
+
+		secrets := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "bee-api", Namespace: "swarm"},
+			Data:       map[string][]byte{"token": []byte("secret")},
+		}).CoreV1()
+
+		req := httptest.NewRequest(http.MethodGet, "/wallet", nil)
+		assert.NoError(t, AuthorizeWithSecret(ctx, req, "bee-api", secrets, "swarm"))
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+
+		assert.Error(t, AuthorizeWithSecret(ctx, req, "bee-api", secrets, "swarm-2"))
+	})
+
+	t.Run("token secret read concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		secrets := blockingSecrets{
+			SecretsGetter: fake.NewSimpleClientset(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "bee-api", Namespace: "swarm"},
+					Data:       map[string][]byte{"token": []byte("secret")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "bee-api", Namespace: "swarm-slow"},
+					Data:       map[string][]byte{"token": []byte("slow")},
+				},
+			).CoreV1(),
+			namespace: "swarm-slow",
+			release:   release,
+		}
+		authorize := NewSecretAuthorizer("bee-api", secrets)
+
+		slowErrC := make(chan error, 1)
+		go func() {
+			slowErrC <- authorize(ctx, httptest.NewRequest(http.MethodGet, "/wallet", nil), "swarm-slow")
+		}()
+
+		// the secret of the other namespace is read while the slow one is
+		// still being read
+		req := httptest.NewRequest(http.MethodGet, "/wallet", nil)
+		assert.NoError(t, authorize(ctx, req, "swarm"))
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+
+		close(release)
+		assert.NoError(t, <-slowErrC)
+	})
+}
+
+// blockingSecrets blocks reading of secrets in the namespace until release is
+// closed.
+type blockingSecrets struct {
+	corev1client.SecretsGetter
+	namespace string
+	release   <-chan struct{}
+}
+
+func (s blockingSecrets) Secrets(namespace string) corev1client.SecretInterface {
+	secrets := s.SecretsGetter.Secrets(namespace)
+	if namespace != s.namespace {
+		return secrets
+	}
+
+	return blockingSecretInterface{SecretInterface: secrets, release: s.release}
+}
+
+type blockingSecretInterface struct {
+	corev1client.SecretInterface
+	release <-chan struct{}
+}
+
+func (s blockingSecretInterface) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	<-s.release
+	return s.SecretInterface.Get(ctx, name, opts)
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+var ErrSpendingCapExceeded = errors.New("spending cap exceeded")
+
+// spendingCap guards transfers of a single token during a funding run
+// against the configured per wallet and total limits.
+type spendingCap struct {
+	mu        sync.Mutex
+	decimals  int
+	perWallet *big.Int // nil when unlimited
+	total     *big.Int // nil when unlimited
+	spent     *big.Int
+}
+
+// spendingCaps holds spending caps of both funded tokens.
+type spendingCaps struct {
+	native *spendingCap
+	swarm  *spendingCap
+}
+
+func newSpendingCap(perWallet, total string, token wallet.Token) (*spendingCap, error) {
+	c := &spendingCap{
+		decimals: token.Decimals,
+		spent:    big.NewInt(0),
+	}
+
+	perWalletAmount, err := parseAmount(perWallet, token)
+	if err != nil {
+		return nil, fmt.Errorf("per wallet limit: %w", err)
+	}
+
+	if perWalletAmount.Sign() > 0 {
+		c.perWallet = perWalletAmount
+	}
+
+	totalAmount, err := parseAmount(total, token)
+	if err != nil {
+		return nil, fmt.Errorf("total limit: %w", err)
+	}
+
+	if totalAmount.Sign() > 0 {
+		c.total = totalAmount
+	}
+
+	return c, nil
+}
+
+// reserve books the amount against the limits, it fails without booking
+// anything when a limit would be exceeded.
+func (c *spendingCap) reserve(amount *big.Int) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.perWallet != nil && amount.Cmp(c.perWallet) > 0 {
+		return fmt.Errorf("%w: amount %s is above per wallet limit %s",
+			ErrSpendingCapExceeded, formatAmount(amount, c.decimals), formatAmount(c.perWallet, c.decimals))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	spent := new(big.Int).Add(c.spent, amount)
+	if c.total != nil && spent.Cmp(c.total) > 0 {
+		return fmt.Errorf("%w: amount %s would bring total transferred to %s, above total limit %s",
+			ErrSpendingCapExceeded, formatAmount(amount, c.decimals), formatAmount(spent, c.decimals), formatAmount(c.total, c.decimals))
+	}
+
+	c.spent = spent
+
+	return nil
+}
+
+// release returns the amount of a transfer that was not made.
+func (c *spendingCap) release(amount *big.Int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.spent = new(big.Int).Sub(c.spent, amount)
+}
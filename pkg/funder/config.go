@@ -4,15 +4,219 @@
 
 package funder
 
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
 type Config struct {
-	Namespace         string
-	Addresses         []string
-	ChainNodeEndpoint string
-	WalletKey         string // Hex encoded key
-	MinAmounts        MinAmounts
+	Namespace            string
+	Namespaces           []string      // additional namespaces, nodes of all of them are funded in a single run
+	AllNamespaces        bool          // nodes in all namespaces are funded, requires a selector
+	LabelSelector        string        // selects bee pods in the namespace, e.g. "app.kubernetes.io/name=bee"
+	FieldSelector        string        // selects bee pods in the namespace, e.g. "status.phase=Running"
+	Kubeconfig           string        // path to kubeconfig file, defaults to KUBECONFIG, $HOME/.kube/config or in-cluster config
+	KubeContext          string        // kubeconfig context, defaults to the current context
+	Inventory            string        // path to inventory file listing nodes, used instead of Kubernetes
+	Docker               bool          // nodes are listed from Docker containers, instead of Kubernetes
+	DockerHost           string        // Docker Engine API address, defaults to DOCKER_HOST or unix:///var/run/docker.sock
+	DockerName           string        // selects bee containers by name, e.g. "^bee-"
+	APIPort              int           // port of bee API of pods, 0 means the container port named "api" or 1633
+	APIScheme            string        // scheme of bee API of pods, http (default) or https
+	APICACertFile        string        // PEM bundle of CAs trusted by https bee API requests, in addition to system CAs
+	APIToken             string        // bearer token of bee API
+	APITokenFile         string        // file with bearer token of bee API
+	APITokenSecret       string        // name of Secret with bearer token of bee API under "token" key, in namespace of each node
+	APIUsername          string        // basic auth username of bee API
+	APIPassword          string        // basic auth password of bee API
+	APITimeout           time.Duration // how long a single bee API request may take, 0 means one minute
+	APIRetries           int           // how many times failed bee API requests are retried, 0 means 3, negative means none
+	Addresses            []string
+	ChainNodeEndpoint    string
+	WalletKey            string           // Hex encoded key
+	WalletKeyFile        string           // file with hex encoded key, instead of WalletKey
+	WalletKeystore       string           // go-ethereum V3 keystore file with encrypted key, instead of WalletKey
+	WalletPassword       string           // password of WalletKeystore
+	WalletPasswordFile   string           // file with password of WalletKeystore, instead of WalletPassword
+	WalletKeySource      wallet.KeySource // provides key of the funding wallet, instead of WalletKey
+	WalletMnemonic       string           // BIP-39 mnemonic the key is derived from, instead of WalletKey, WalletPassword is its passphrase
+	WalletMnemonicFile   string           // file with WalletMnemonic
+	WalletDerivationPath string           // BIP-44 base path of accounts of WalletMnemonic, defaults to m/44'/60'/0'/0
+	WalletIndex          uint32           // index of the account of WalletMnemonic under WalletDerivationPath
+	WalletSigner         string           // JSON-RPC endpoint of a remote signer (e.g. Clef) signing instead of a local key
+	WalletSignerMethod   string           // sign method of WalletSigner, eth_signTransaction (default) or account_signTransaction (Clef)
+	WalletAddress        string           // address of the funding wallet in WalletSigner, defaults to the only account of the signer
+	MinAmounts           MinAmounts       // wallets below these amounts are topped up
+	TargetAmounts        TargetAmounts    // amounts wallets are topped up to, defaults to MinAmounts
+	MaxTotal             MaxAmounts       // limits amounts transferred to all wallets in a single run
+	MaxPerWallet         MaxAmounts       // limits amounts transferred to a single wallet
+	Confirmations        uint64           // blocks mined on top of a transfer before it is considered done
+	ReceiptTimeout       time.Duration    // how long to wait for a transfer to be mined
+	Interval             time.Duration    // how often Watch re-evaluates balances
+	ShutdownTimeout      time.Duration    // how long Watch lets in-flight transfers finish on shutdown
+	WatchDebounce        time.Duration    // how long WatchNodes waits for pod events of a node to settle
+	ReadyTimeout         time.Duration    // how long to wait for pods to become ready, not ready pods are skipped
+	PrioritizedFunding   bool             // funds wallets with the lowest balance first when the budget is short, instead of aborting
+	AllowPartialStake    bool             // staking succeeds when staking of some, but not all, nodes fails
+}
+
+// namespaces returns all configured namespaces. Empty namespace name stands
+// for all namespaces, which are listed outside of Kubernetes by default.
+func (c Config) namespaces() []string {
+	if c.AllNamespaces {
+		return []string{""}
+	}
+
+	result := make([]string, 0, len(c.Namespaces)+1)
+	for _, ns := range append([]string{c.Namespace}, c.Namespaces...) {
+		if ns != "" && !slices.Contains(result, ns) {
+			result = append(result, ns)
+		}
+	}
+
+	if len(result) == 0 && c.outsideKubernetes() {
+		return []string{""}
+	}
+
+	return result
+}
+
+// outsideKubernetes reports whether nodes are listed from the inventory or
+// Docker, instead of Kubernetes.
+func (c Config) outsideKubernetes() bool {
+	return c.Inventory != "" || c.Docker
+}
+
+// hasNamespaces reports whether nodes are looked up in namespaces, instead of
+// funding configured addresses.
+func (c Config) hasNamespaces() bool {
+	return len(c.namespaces()) > 0
+}
+
+// namespacesString describes configured namespaces in logs.
+func (c Config) namespacesString() string {
+	if c.AllNamespaces {
+		return "all"
+	}
+
+	return strings.Join(c.namespaces(), ",")
+}
+
+// ErrSelectorRequired is returned when nodes of all namespaces should be
+// listed without a label selector.
+var ErrSelectorRequired = errors.New("label selector is required with all namespaces")
+
+// ErrInventoryWithAddresses is returned when both inventory and addresses are
+// configured, as only nodes of the inventory would be funded.
+var ErrInventoryWithAddresses = errors.New("inventory and addresses cannot be set together")
+
+// validateNamespaces checks nodes of all namespaces are selected by a label
+// selector, as pod names of unrelated workloads could look like bee nodes.
+// Field selectors, e.g. status.phase=Running, match unrelated pods as well.
+func (c Config) validateNamespaces() error {
+	if c.AllNamespaces && !c.hasSelector() && !c.outsideKubernetes() {
+		return ErrSelectorRequired
+	}
+
+	return nil
+}
+
+// hasSelector reports whether bee pods are selected with a label selector,
+// instead of guessing them from pod names. Field selector only narrows pods
+// further.
+func (c Config) hasSelector() bool {
+	return c.LabelSelector != ""
+}
+
+// filterByName reports whether nodes which do not look like bee nodes by their
+// name are omitted. Nodes selected by a label selector or container name, or
+// listed in the inventory are bee nodes.
+func (c Config) filterByName() bool {
+	return !c.hasSelector() && c.DockerName == "" && c.Inventory == ""
 }
 
+// MinAmounts are amounts wallets should have. Like all configured amounts,
+// they are decimal token amounts (e.g. "0.1"), or integer amounts in token
+// base units suffixed with the base unit name (e.g. "100000wei" or "1000plur").
 type MinAmounts struct {
-	NativeCoin float64 // on mainnet this is xDAI
-	SwarmToken float64 // on mainnet this is xBZZ
+	NativeCoin string // on mainnet this is xDAI
+	SwarmToken string // on mainnet this is xBZZ
+}
+
+// TargetAmounts are amounts wallets are topped up to once their balance drops
+// below MinAmounts. Empty value or value below the min amount means wallets are
+// topped up to the min amount.
+type TargetAmounts struct {
+	NativeCoin string // on mainnet this is xDAI
+	SwarmToken string // on mainnet this is xBZZ
+}
+
+// MaxAmounts limits transferred amounts, empty or zero value means no limit.
+type MaxAmounts struct {
+	NativeCoin string
+	SwarmToken string
+}
+
+// topUpPolicy refills a balance that dropped below the min amount (low-water
+// threshold) up to the target amount (high-water mark).
+type topUpPolicy struct {
+	min    *big.Int
+	target *big.Int
+}
+
+func newTopUpPolicy(minVal, targetVal string, token wallet.Token) (topUpPolicy, error) {
+	minAmount, err := parseAmount(minVal, token)
+	if err != nil {
+		return topUpPolicy{}, fmt.Errorf("min amount: %w", err)
+	}
+
+	targetAmount, err := parseAmount(targetVal, token)
+	if err != nil {
+		return topUpPolicy{}, fmt.Errorf("target amount: %w", err)
+	}
+
+	return topUpPolicy{min: minAmount, target: targetAmount}, nil
+}
+
+type fundingPolicy struct {
+	native topUpPolicy
+	swarm  topUpPolicy
+}
+
+func (c Config) fundingPolicy(nativeCoin, swarmToken wallet.Token) (fundingPolicy, error) {
+	native, err := newTopUpPolicy(c.MinAmounts.NativeCoin, c.TargetAmounts.NativeCoin, nativeCoin)
+	if err != nil {
+		return fundingPolicy{}, fmt.Errorf("invalid native coin %w", err)
+	}
+
+	swarm, err := newTopUpPolicy(c.MinAmounts.SwarmToken, c.TargetAmounts.SwarmToken, swarmToken)
+	if err != nil {
+		return fundingPolicy{}, fmt.Errorf("invalid swarm token %w", err)
+	}
+
+	return fundingPolicy{native: native, swarm: swarm}, nil
+}
+
+// hasWalletKey reports whether any source of the funding wallet key is set.
+func (c Config) hasWalletKey() bool {
+	return c.WalletKeySource != nil || c.WalletKey != "" || c.WalletKeyFile != "" || c.WalletKeystore != "" || c.hasWalletMnemonic()
+}
+
+func (c Config) hasWalletMnemonic() bool {
+	return c.WalletMnemonic != "" || c.WalletMnemonicFile != ""
+}
+
+// derivationPath returns BIP-44 base path of accounts of the wallet mnemonic.
+func (c Config) derivationPath() string {
+	if c.WalletDerivationPath == "" {
+		return wallet.DefaultDerivationPath
+	}
+
+	return c.WalletDerivationPath
 }
@@ -0,0 +1,267 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	defaultDockerHost = "unix:///var/run/docker.sock"
+	// composeProjectLabel is label of containers started by docker compose,
+	// its value is used as namespace of the nodes.
+	composeProjectLabel = "com.docker.compose.project"
+)
+
+// ErrDockerFieldSelector is returned when Docker containers should be selected
+// by a Kubernetes field selector.
+var ErrDockerFieldSelector = errors.New("field selector is not supported with docker")
+
+// dockerLister is NodeLister of bee containers, listed by Docker Engine API.
+// Namespace of a node is docker compose project of its container.
+type dockerLister struct {
+	client  *http.Client
+	baseURL string
+	// host of published ports when they are published on all interfaces
+	publishedHost string
+	labels        []string // label filters, e.g. "app=bee"
+	name          string   // container name filter
+	api           beeAPI
+}
+
+func newDockerLister(cfg Config) (NodeLister, error) {
+	api, err := newBeeAPI(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.FieldSelector != "" {
+		return nil, ErrDockerFieldSelector
+	}
+
+	host := cfg.DockerHost
+	if host == "" {
+		host = os.Getenv("DOCKER_HOST")
+	}
+
+	if host == "" {
+		host = defaultDockerHost
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker host %q: %w", host, err)
+	}
+
+	l := &dockerLister{
+		name: cfg.DockerName,
+		api:  api,
+	}
+
+	switch u.Scheme {
+	case "unix":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", u.Path)
+		}
+
+		l.client = &http.Client{Transport: transport}
+		l.baseURL = "http://docker"
+		l.publishedHost = "localhost"
+	case "tcp", "http":
+		l.client = &http.Client{}
+		l.baseURL = "http://" + u.Host
+		l.publishedHost = u.Hostname()
+	default:
+		return nil, fmt.Errorf("unsupported docker host %q", host)
+	}
+
+	if cfg.LabelSelector != "" {
+		selector, err := labels.Parse(cfg.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+
+		requirements, _ := selector.Requirements()
+		for _, r := range requirements {
+			if r.Operator() != "=" && r.Operator() != "==" {
+				return nil, fmt.Errorf("docker supports only equality label selectors: %s", r.String())
+			}
+
+			l.labels = append(l.labels, r.Key()+"="+r.Values().List()[0])
+		}
+	}
+
+	return l, nil
+}
+
+type dockerContainer struct {
+	Names  []string          `json:"Names"`
+	Labels map[string]string `json:"Labels"`
+	State  string            `json:"State"`
+	Status string            `json:"Status"`
+	Ports  []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+func (l *dockerLister) List(ctx context.Context, namespace string) ([]NodeInfo, error) {
+	filters := make(map[string][]string)
+	filters["label"] = slices.Clone(l.labels)
+
+	if namespace != "" {
+		filters["label"] = append(filters["label"], composeProjectLabel+"="+namespace)
+	}
+
+	if l.name != "" {
+		filters["name"] = []string{l.name}
+	}
+
+	f, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// stopped containers are listed too, so they are reported as skipped
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/containers/json?all=1&filters="+url.QueryEscape(string(f)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing containers: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return nil, fmt.Errorf("failed listing containers: status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var containers []dockerContainer
+	if err := json.NewDecoder(resp.Body).Decode(&containers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal containers: %w", err)
+	}
+
+	result := make([]NodeInfo, 0, len(containers))
+	for _, c := range containers {
+		result = append(result, l.nodeInfo(c))
+	}
+
+	return result, nil
+}
+
+func (l *dockerLister) nodeInfo(c dockerContainer) NodeInfo {
+	node := NodeInfo{
+		Namespace:  c.Labels[composeProjectLabel],
+		SkipReason: containerNotReadyReason(c),
+	}
+
+	if len(c.Names) > 0 {
+		node.Name = strings.TrimPrefix(c.Names[0], "/")
+	}
+
+	address, err := l.address(c)
+	if err != nil && node.SkipReason == "" {
+		node.SkipReason = err.Error()
+	}
+
+	node.Address = address
+
+	return node
+}
+
+// containerNotReadyReason returns why the container is not ready, or empty
+// string when it is ready.
+func containerNotReadyReason(c dockerContainer) string {
+	if c.State != "running" {
+		return fmt.Sprintf("container is %s", c.State)
+	}
+
+	if strings.Contains(c.Status, "(health: starting)") || strings.Contains(c.Status, "(unhealthy)") {
+		return fmt.Sprintf("container is not healthy (%s)", c.Status)
+	}
+
+	return ""
+}
+
+// address returns bee API address of the container, at the published port
+// when the API port is published, or at the container IP otherwise. Container
+// labels override the configured port and scheme, like pod annotations.
+func (l *dockerLister) address(c dockerContainer) (string, error) {
+	scheme := l.api.scheme
+	if s, ok := c.Labels[APISchemeAnnotation]; ok {
+		if err := validateAPIScheme(s); err != nil {
+			return "", fmt.Errorf("label %s: %w", APISchemeAnnotation, err)
+		}
+
+		scheme = s
+	}
+
+	port := l.api.port
+	if port == 0 {
+		port = defaultAPIPort
+	}
+
+	if v, ok := c.Labels[APIPortAnnotation]; ok {
+		p, err := strconv.Atoi(v)
+		if err != nil || p <= 0 || p > 65535 {
+			return "", fmt.Errorf("label %s: invalid port %q", APIPortAnnotation, v)
+		}
+
+		port = p
+	}
+
+	for _, p := range c.Ports {
+		if p.PrivatePort != port || p.PublicPort == 0 || p.Type != "tcp" {
+			continue
+		}
+
+		host := p.IP
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = l.publishedHost
+		}
+
+		return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(p.PublicPort))), nil
+	}
+
+	networks := make([]string, 0, len(c.NetworkSettings.Networks))
+	for name := range c.NetworkSettings.Networks {
+		networks = append(networks, name)
+	}
+
+	slices.Sort(networks)
+
+	for _, name := range networks {
+		if ip := c.NetworkSettings.Networks[name].IPAddress; ip != "" {
+			return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(ip, strconv.Itoa(port))), nil
+		}
+	}
+
+	return "", fmt.Errorf("container has no published port %d or network IP", port)
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+func Test_Docker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := generateKey(t)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+
+	bee := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body string
+		switch req.URL.Path {
+		case "/wallet":
+			// wallets of containers differ by the host their API is reached at
+			address := "0x95f8916183f7C7154e49396507F5b0FafA4d8077"
+			if strings.HasPrefix(req.Host, "127.0.0.1:") {
+				address = "0x4C4E453E72aF9939A27cac5a09ba583d72c4DfF0"
+			}
+
+			body = `{"walletAddress": "` + address + `", "chainID": 100}`
+		case "/stake":
+			body = `{"stakedAmount": "0"}`
+		}
+
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(bee.Close)
+
+	beeURL, err := url.Parse(bee.URL)
+	assert.NoError(t, err)
+	beePort, err := strconv.Atoi(beeURL.Port())
+	assert.NoError(t, err)
+
+	// containers of two compose projects, the bee API of "lab" project is
+	// published, and of "swarm" project reachable at the container IP
+	containers := fmt.Sprintf(`[
+		{
+			"Names": ["/lab-bee-1"],
+			"Labels": {"com.docker.compose.project": "lab", "app": "bee"},
+			"State": "running",
+			"Status": "Up 2 minutes (healthy)",
+			"Ports": [{"IP": "0.0.0.0", "PrivatePort": 1633, "PublicPort": %d, "Type": "tcp"}]
+		},
+		{
+			"Names": ["/swarm-bee-1"],
+			"Labels": {"com.docker.compose.project": "swarm", "app": "bee", "node-funder/api-port": "%d"},
+			"State": "running",
+			"Status": "Up 2 minutes",
+			"NetworkSettings": {"Networks": {"swarm_default": {"IPAddress": "127.0.0.1"}}}
+		},
+		{
+			"Names": ["/swarm-bee-2"],
+			"Labels": {"com.docker.compose.project": "swarm", "app": "bee"},
+			"State": "running",
+			"Status": "Up 1 second (health: starting)",
+			"NetworkSettings": {"Networks": {"swarm_default": {"IPAddress": "127.0.0.2"}}}
+		},
+		{
+			"Names": ["/swarm-bee-3"],
+			"Labels": {"com.docker.compose.project": "swarm", "app": "bee"},
+			"State": "exited",
+			"Status": "Exited (1) 5 minutes ago"
+		}
+	]`, beePort, beePort)
+
+	dir, err := os.MkdirTemp("", "docker")
+	assert.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, os.RemoveAll(dir)) })
+
+	socket := filepath.Join(dir, "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+
+	docker := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/containers/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var filters map[string][]string
+		assert.NoError(t, json.Unmarshal([]byte(req.URL.Query().Get("filters")), &filters))
+		assert.Contains(t, filters["label"], "app=bee")
+
+		var result []json.RawMessage
+		assert.NoError(t, json.Unmarshal([]byte(containers), &result))
+
+		// stopped containers are listed only with all=1
+		if req.URL.Query().Get("all") != "1" {
+			running := result[:0]
+			for _, c := range result {
+				var container struct{ State string }
+				assert.NoError(t, json.Unmarshal(c, &container))
+				if container.State == "running" {
+					running = append(running, c)
+				}
+			}
+			result = running
+		}
+
+		// emulate the compose project filter
+		if len(filters["label"]) > 1 {
+			project := filters["label"][1]
+			filtered := result[:0]
+			for _, c := range result {
+				var container struct{ Labels map[string]string }
+				assert.NoError(t, json.Unmarshal(c, &container))
+				if "com.docker.compose.project="+container.Labels["com.docker.compose.project"] == project {
+					filtered = append(filtered, c)
+				}
+			}
+			result = filtered
+		}
+
+		assert.NoError(t, json.NewEncoder(w).Encode(result))
+	}))
+	docker.Listener = listener
+	docker.Start()
+	t.Cleanup(docker.Close)
+
+	cfg := Config{
+		Docker:        true,
+		DockerHost:    "unix://" + socket,
+		LabelSelector: "app=bee",
+		MinAmounts:    MinAmounts{NativeCoin: "3", SwarmToken: "1"},
+	}
+
+	t.Run("fund", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := Fund(ctx, cfg, nil, w)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Totals.Funded)
+		assert.ElementsMatch(t, []SkippedNode{
+			{Name: "swarm-bee-2", Namespace: "swarm", Reason: "container is not healthy (Up 1 second (health: starting))"},
+			{Name: "swarm-bee-3", Namespace: "swarm", Reason: "container is exited"},
+		}, report.SkippedNodes)
+	})
+
+	t.Run("fund namespace", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := cfg
+		cfg.Namespace = "lab"
+		report, err := Fund(ctx, cfg, nil, w)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Totals.Funded)
+		assert.Empty(t, report.SkippedNodes)
+	})
+
+	t.Run("stake", func(t *testing.T) {
+		t.Parallel()
+
+		report, err := Stake(ctx, cfg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Totals.Staked)
+	})
+
+	t.Run("set based selector", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := cfg
+		cfg.LabelSelector = "app in (bee)"
+		_, err := Stake(ctx, cfg, nil)
+		assert.ErrorContains(t, err, "docker supports only equality label selectors")
+	})
+
+	t.Run("field selector", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := cfg
+		cfg.FieldSelector = "status.phase=Running"
+		_, err := Stake(ctx, cfg, nil)
+		assert.ErrorIs(t, err, ErrDockerFieldSelector)
+	})
+}
@@ -4,12 +4,59 @@
 
 package funder
 
-import "math/big"
+import (
+	"context"
+	"math/big"
+	"net/http"
 
-func CalcTopUpAmount(minVal float64, currAmount *big.Int, decimals int) *big.Int {
-	return calcTopUpAmount(minVal, currAmount, decimals)
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	corev1 "k8s.io/api/core/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+func CalcTopUpAmount(minVal, targetVal string, currAmount *big.Int, token wallet.Token) (*big.Int, error) {
+	policy, err := newTopUpPolicy(minVal, targetVal, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return calcTopUpAmount(policy, currAmount), nil
+}
+
+func ParseAmount(value string, token wallet.Token) (*big.Int, error) {
+	return parseAmount(value, token)
 }
 
 func FormatAmount(amount *big.Int, decimals int) string {
 	return formatAmount(amount, decimals)
 }
+
+func NodeInfoFromPod(pod *corev1.Pod, cfg Config) (NodeInfo, error) {
+	api, err := newBeeAPI(cfg)
+	if err != nil {
+		return NodeInfo{}, err
+	}
+
+	return nodeInfoFromPod(pod, api), nil
+}
+
+func AuthorizeWithSecret(ctx context.Context, req *http.Request, secret string, secrets corev1client.SecretsGetter, namespace string) error {
+	return NewSecretAuthorizer(secret, secrets)(ctx, req, namespace)
+}
+
+func NewSecretAuthorizer(secret string, secrets corev1client.SecretsGetter) func(ctx context.Context, req *http.Request, namespace string) error {
+	return newSecretAPIAuth(secret, secrets).authorize
+}
+
+func MakeConfig(kubeconfig, kubeContext string) (*rest.Config, error) {
+	return makeConfig(kubeconfig, kubeContext)
+}
+
+func MakeWalletKey(cfg Config) (wallet.Key, error) {
+	return makeWalletKey(cfg)
+}
+
+func MakeWalletSigner(ctx context.Context, cfg Config) (wallet.Signer, error) {
+	return makeWalletSigner(ctx, cfg)
+}
@@ -23,7 +23,11 @@ type FunderOptions func(*Options)
 
 // Options represents funder options
 type Options struct {
-	log logging.Logger
+	log         logging.Logger
+	concurrency int
+	metrics     *Metrics
+	caps        *spendingCaps     // shared by all runs, nil makes caps of each run
+	pending     *pendingTransfers // transfers of previous runs, nil when not tracked
 }
 
 // DefaultOptions returns default options
@@ -40,13 +44,49 @@ func WithLoggerOption(log logging.Logger) FunderOptions {
 	}
 }
 
+// WithMetricsOption sets metrics the funder and its funding wallet update,
+// nil means no metrics
+func WithMetricsOption(m *Metrics) FunderOptions {
+	return func(o *Options) {
+		o.metrics = m
+	}
+}
+
+// WithConcurrencyOption limits the number of nodes or wallets processed at
+// once, bounding in-flight bee API calls, balance queries and transfers.
+// Zero means no limit.
+func WithConcurrencyOption(concurrency int) FunderOptions {
+	return func(o *Options) {
+		o.concurrency = concurrency
+	}
+}
+
+// withSpendingCaps makes transfers of all runs count against the same caps,
+// instead of caps of each run.
+func withSpendingCaps(caps spendingCaps) FunderOptions {
+	return func(o *Options) {
+		o.caps = &caps
+	}
+}
+
+// withPendingTransfers makes runs skip wallets whose transfers sent by previous
+// runs are still pending, and record transfers they leave pending.
+func withPendingTransfers(pending *pendingTransfers) FunderOptions {
+	return func(o *Options) {
+		o.pending = pending
+	}
+}
+
+// Fund tops up wallets of nodes in the namespace, or the configured addresses,
+// and returns report of the run. The report is returned also when funding of
+// some wallets failed.
 func Fund(
 	ctx context.Context,
 	cfg Config,
 	nl NodeLister,
 	fundingWallet *wallet.Wallet,
 	options ...FunderOptions,
-) error {
+) (FundReport, error) {
 	var err error
 
 	opts := DefaultOptions()
@@ -55,10 +95,12 @@ func Fund(
 	}
 
 	if fundingWallet == nil {
-		fundingWallet, err = makeFundingWallet(ctx, cfg)
+		fundingWallet, err = makeFundingWallet(ctx, cfg, opts.metrics.walletOptions()...)
 		if err != nil {
-			return fmt.Errorf("make funding wallet: %w", err)
+			return FundReport{}, fmt.Errorf("make funding wallet: %w", err)
 		}
+
+		defer fundingWallet.Close()
 	}
 
 	opts.log.Infof("node funder started...")
@@ -66,73 +108,286 @@ func Fund(
 
 	opts.log.Infof("using wallet address (public key address): %s", fundingWallet.PublicAddress())
 
-	if cfg.Namespace != "" {
+	nativeCoin, swarmToken, err := fundingTokens(ctx, fundingWallet)
+	if err != nil {
+		return FundReport{}, err
+	}
+
+	report := newFundReport(fundingWallet.PublicAddress(), nativeCoin, swarmToken)
+
+	policy, err := cfg.fundingPolicy(nativeCoin, swarmToken)
+	if err != nil {
+		return report, err
+	}
+
+	caps, err := opts.spendingCaps(cfg, nativeCoin, swarmToken)
+	if err != nil {
+		return report, err
+	}
+
+	pool := newWorkerPool(opts.concurrency)
+
+	wallets, unfetched, skippedNodes, err := listWallets(ctx, cfg, nl, fundingWallet, pool, opts.log)
+	if err != nil {
+		return report, err
+	}
+
+	report.addSkippedNodes(skippedNodes...)
+	report.add(unfetched...)
+
+	wallets, pendingSkipped := opts.pending.filter(ctx, fundingWallet, wallets)
+	for _, wr := range pendingSkipped {
+		opts.log.Warningf("%s funding skipped - %v", wr.Wallet.Name, wr.Err)
+	}
+
+	report.add(pendingSkipped...)
+
+	wallets, skipped, err := checkBudget(ctx, cfg, policy, fundingWallet, wallets, pool, opts.log)
+	if err != nil {
+		return report, err
+	}
+
+	opts.log.Infof("funding wallets (count=%d) below amounts=%+v up to amounts=%+v", len(wallets), cfg.MinAmounts, cfg.TargetAmounts)
+
+	funded := fundAllWallets(ctx, fundingWallet, policy, caps, wallets, pool, opts.log)
+	opts.pending.add(funded)
+
+	report.add(funded...)
+	report.add(skipped...)
+
+	opts.metrics.observeFundReport(report)
+	opts.metrics.observeFundingBalance(ctx, fundingWallet, nativeCoin, swarmToken, opts.log)
+
+	// nodes whose wallet could not be fetched are not funded
+	failed := len(unfetched) > 0
+
+	for _, wr := range funded {
+		if wr.Status == WalletStatusFunded || wr.Status == WalletStatusAlreadyFunded {
+			continue
+		}
+
+		if errors.Is(wr.Err, ErrSpendingCapExceeded) {
+			return report, fmt.Errorf("funding all wallets failed: %w", wr.Err)
+		}
+
+		failed = true
+	}
+
+	if failed {
+		return report, fmt.Errorf("funding all wallets failed")
+	}
+
+	return report, nil
+}
+
+// listWallets returns wallets of all ready nodes in the namespace, reports of
+// nodes whose wallet could not be fetched and the nodes which are not ready,
+// when it is configured, or wallets of the configured addresses otherwise.
+func listWallets(
+	ctx context.Context,
+	cfg Config,
+	nl NodeLister,
+	fundingWallet *wallet.Wallet,
+	pool *workerPool,
+	log logging.Logger,
+) ([]WalletInfo, []WalletReport, []NodeInfo, error) {
+	if cfg.Inventory != "" && len(cfg.Addresses) > 0 {
+		return nil, nil, nil, ErrInventoryWithAddresses
+	}
+
+	if cfg.hasNamespaces() {
 		if nl == nil {
-			nl, err = newNodeLister()
+			var err error
+
+			nl, err = newNodeLister(cfg)
 			if err != nil {
-				return fmt.Errorf("make node lister: %w", err)
+				return nil, nil, nil, fmt.Errorf("make node lister: %w", err)
 			}
 		}
 
-		return fundNamespace(ctx, cfg, nl, fundingWallet, opts.log)
+		wallets, failed, skipped, err := namespaceWallets(ctx, cfg, nl, fundingWallet, pool, log)
+
+		return uniqueWallets(wallets, log), failed, skipped, err
+	}
+
+	wallets, err := addressWallets(ctx, cfg, fundingWallet)
+
+	return uniqueWallets(wallets, log), nil, nil, err
+}
+
+// uniqueWallets omits wallets with address of a previous wallet, so a wallet
+// shared by nodes, e.g. in several namespaces, is funded once.
+func uniqueWallets(wallets []WalletInfo, log logging.Logger) []WalletInfo {
+	seen := make(map[common.Address]WalletInfo, len(wallets))
+	result := wallets[:0]
+
+	for _, w := range wallets {
+		address := common.HexToAddress(w.Address)
+		if first, ok := seen[address]; ok {
+			log.Warningf("%s has the same address as %s, it is funded once", w.Name, first.Name)
+			continue
+		}
+
+		seen[address] = w
+		result = append(result, w)
 	}
 
-	return fundAddresses(ctx, cfg, fundingWallet, opts.log)
+	return result
 }
 
-func fundNamespace(
+func namespaceWallets(
 	ctx context.Context,
 	cfg Config,
 	nl NodeLister,
 	fundingWallet *wallet.Wallet,
+	pool *workerPool,
 	log logging.Logger,
-) (err error) {
-	log.Infof("fetching nodes for namespace=%s", cfg.Namespace)
+) (_ []WalletInfo, _ []WalletReport, _ []NodeInfo, err error) {
+	log.Infof("fetching nodes for namespace=%s", cfg.namespacesString())
 
 	var chainID int64
 	if cfg.ChainNodeEndpoint != "" {
 		chainID, err = fundingWallet.ChainID(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to fetch chainID from ChainNodeEndpoint: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to fetch chainID from ChainNodeEndpoint: %w", err)
 		}
 
 		log.Infof("using specified ChainNodeEndpoint to retrieve funding chainID: %d", chainID)
 	}
 
-	namespace, err := fetchNamespaceNodeInfo(ctx, cfg.Namespace, chainID, nl, log)
+	namespace, err := fetchNamespaceNodeInfo(ctx, cfg, chainID, nl, pool, log)
 	if err != nil {
-		return fmt.Errorf("fetching namespace nodes failed: %w", err)
+		return nil, nil, nil, fmt.Errorf("fetching namespace nodes failed: %w", err)
 	}
 
-	log.Infof("funding nodes (count=%d) up to amounts=%+v", len(namespace.NodeWallets), cfg.MinAmounts)
+	return namespace.NodeWallets, namespace.FailedWallets, namespace.SkippedNodes, nil
+}
 
-	if ok := fundAllWallets(ctx, fundingWallet, cfg.MinAmounts, namespace.NodeWallets, log); !ok {
-		return fmt.Errorf("funding all nodes failed")
+func addressWallets(
+	ctx context.Context,
+	cfg Config,
+	fundingWallet *wallet.Wallet,
+) ([]WalletInfo, error) {
+	cid, err := fundingWallet.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting funding wallet's chain ID failed: %w", err)
 	}
 
-	return nil
+	return makeWalletInfoFromAddresses(cfg.Addresses, cid), nil
 }
 
-func fundAddresses(
+// checkBudget verifies the funding wallet can cover top-ups of all wallets
+// before any transfer is made. When it cannot, the funding is aborted, or
+// only the prioritized subset of wallets is returned, with reports of the
+// skipped ones, when configured so. Funding is aborted also when top-ups of
+// some wallets could not be planned, unless prioritized funding is
+// configured, which reports their funding failures instead.
+func checkBudget(
 	ctx context.Context,
 	cfg Config,
+	policy fundingPolicy,
 	fundingWallet *wallet.Wallet,
+	wallets []WalletInfo,
+	pool *workerPool,
 	log logging.Logger,
-) error {
+) (covered []WalletInfo, skipped []WalletReport, err error) {
+	plan, err := makePlan(ctx, fundingWallet, policy, wallets, pool)
+	if err != nil {
+		return nil, nil, fmt.Errorf("planning funding failed: %w", err)
+	}
+
+	if plan.Covered() {
+		return wallets, nil, nil
+	}
+
+	missing := plan.Shortfall()
+	if missing.NativeCoin.Sign() == 0 && missing.SwarmToken.Sign() == 0 {
+		if cfg.PrioritizedFunding {
+			return wallets, nil, nil
+		}
+
+		for _, wp := range plan.Wallets {
+			if wp.Err != nil {
+				return nil, nil, fmt.Errorf("planning funding of %s failed: %w", wp.Wallet.Name, wp.Err)
+			}
+		}
+	}
+
+	required := plan.Required()
+	err = fmt.Errorf("%w: short of %s %s and %s %s (required %s %s and %s %s, available %s %s and %s %s)",
+		ErrInsufficientBudget,
+		formatAmount(missing.NativeCoin, plan.NativeCoin.Decimals), plan.NativeCoin.Symbol,
+		formatAmount(missing.SwarmToken, plan.SwarmToken.Decimals), plan.SwarmToken.Symbol,
+		formatAmount(required.NativeCoin, plan.NativeCoin.Decimals), plan.NativeCoin.Symbol,
+		formatAmount(required.SwarmToken, plan.SwarmToken.Decimals), plan.SwarmToken.Symbol,
+		formatAmount(plan.FundingBalance.NativeCoin, plan.NativeCoin.Decimals), plan.NativeCoin.Symbol,
+		formatAmount(plan.FundingBalance.SwarmToken, plan.SwarmToken.Decimals), plan.SwarmToken.Symbol,
+	)
+
+	if !cfg.PrioritizedFunding {
+		return nil, nil, err
+	}
+
+	covered, skippedWallets := plan.Prioritize()
+
+	log.Errorf("%v; funding prioritized wallets (count=%d) only", err, len(covered))
+
+	for _, wi := range skippedWallets {
+		log.Errorf("%s funding skipped - insufficient funding wallet balance", wi.Name)
+
+		skipped = append(skipped, WalletReport{
+			Wallet:      wi,
+			Status:      WalletStatusSkipped,
+			Transferred: newAmounts(),
+			Err:         ErrInsufficientBudget,
+		})
+	}
+
+	return covered, skipped, nil
+}
+
+// fundingTokens returns tokens of the funding wallet's chain.
+func fundingTokens(ctx context.Context, fundingWallet *wallet.Wallet) (nativeCoin, swarmToken wallet.Token, err error) {
 	cid, err := fundingWallet.ChainID(ctx)
 	if err != nil {
-		return fmt.Errorf("getting funding wallet's chain ID failed: %w", err)
+		return wallet.Token{}, wallet.Token{}, fmt.Errorf("getting funding wallet's chain ID failed: %w", err)
+	}
+
+	nativeCoin, err = wallet.NativeCoinForChain(cid)
+	if err != nil {
+		return wallet.Token{}, wallet.Token{}, err
 	}
 
-	wallets := makeWalletInfoFromAddresses(cfg.Addresses, cid)
+	swarmToken, err = wallet.SwarmTokenForChain(cid)
+	if err != nil {
+		return wallet.Token{}, wallet.Token{}, err
+	}
 
-	log.Infof("funding wallets (count=%d) up to amounts=%+v", len(wallets), cfg.MinAmounts)
+	return nativeCoin, swarmToken, nil
+}
 
-	if ok := fundAllWallets(ctx, fundingWallet, cfg.MinAmounts, wallets, log); !ok {
-		return fmt.Errorf("funding all wallets failed")
+// spendingCaps returns the shared spending caps, or caps of a single run
+// when they are not set.
+func (o *Options) spendingCaps(cfg Config, nativeCoin, swarmToken wallet.Token) (spendingCaps, error) {
+	if o.caps != nil {
+		return *o.caps, nil
 	}
 
-	return nil
+	return makeSpendingCaps(cfg, nativeCoin, swarmToken)
+}
+
+func makeSpendingCaps(cfg Config, nativeCoin, swarmToken wallet.Token) (spendingCaps, error) {
+	native, err := newSpendingCap(cfg.MaxPerWallet.NativeCoin, cfg.MaxTotal.NativeCoin, nativeCoin)
+	if err != nil {
+		return spendingCaps{}, fmt.Errorf("invalid native coin %w", err)
+	}
+
+	swarm, err := newSpendingCap(cfg.MaxPerWallet.SwarmToken, cfg.MaxTotal.SwarmToken, swarmToken)
+	if err != nil {
+		return spendingCaps{}, fmt.Errorf("invalid swarm token %w", err)
+	}
+
+	return spendingCaps{native: native, swarm: swarm}, nil
 }
 
 func makeWalletInfoFromAddresses(addrs []string, cid int64) []WalletInfo {
@@ -151,54 +406,53 @@ func makeWalletInfoFromAddresses(addrs []string, cid int64) []WalletInfo {
 func fundAllWallets(
 	ctx context.Context,
 	fundingWallet *wallet.Wallet,
-	minAmounts MinAmounts,
+	policy fundingPolicy,
+	caps spendingCaps,
 	wallets []WalletInfo,
+	pool *workerPool,
 	log logging.Logger,
-) bool {
-	fundWalletRespC := make([]<-chan fundWalletResp, len(wallets))
+) []WalletReport {
+	fundWalletRespC := make([]<-chan WalletReport, len(wallets))
 	for i, wi := range wallets {
-		fundWalletRespC[i] = fundWalletAsync(ctx, fundingWallet, minAmounts, wi)
+		fundWalletRespC[i] = fundWalletAsync(ctx, fundingWallet, policy, caps, wi, pool)
 	}
 
-	allWalletsFunded := true
+	reports := make([]WalletReport, 0, len(wallets))
 
 	for _, respC := range fundWalletRespC {
 		resp := <-respC
-		name := resp.wallet.Name
-		cid := resp.wallet.ChainID
-
-		if resp.err != nil {
-			log.Errorf("%s funding failed - error: %s", name, resp.err.Error())
-
-			allWalletsFunded = false
-
-			continue
-		}
-
-		if resp.transferredNativeAmount == nil && resp.transferredSwarmAmount == nil {
+		name := resp.Wallet.Name
+		cid := resp.Wallet.ChainID
+
+		reports = append(reports, resp)
+
+		switch resp.Status {
+		case WalletStatusSkipped:
+			log.Errorf("%s funding skipped - %s", name, resp.Err.Error())
+		case WalletStatusFailed:
+			log.Errorf("%s funding failed - error: %s", name, resp.Err.Error())
+		case WalletStatusPartiallyFunded:
+			log.Errorf("%s funded partially - error: %s", name, resp.Err.Error())
+		case WalletStatusAlreadyFunded:
 			log.Infof("%s funded - already funded", name)
-		} else {
+		case WalletStatusFunded:
 			token, _ := wallet.NativeCoinForChain(cid)
-			nativeAmount := formatAmount(resp.transferredNativeAmount, token.Decimals)
+			nativeAmount := formatAmount(resp.Transferred.NativeCoin, token.Decimals)
 			token, _ = wallet.SwarmTokenForChain(cid)
-			swarmAmount := formatAmount(resp.transferredSwarmAmount, token.Decimals)
+			swarmAmount := formatAmount(resp.Transferred.SwarmToken, token.Decimals)
 
-			log.Infof("%s funded - transferred { native: %s, swarm: %s }", name, nativeAmount, swarmAmount)
+			log.Infof("%s funded - transferred and mined { native: %s (tx %s), swarm: %s (tx %s) }",
+				name, nativeAmount, formatTxHash(resp.NativeTxHash), swarmAmount, formatTxHash(resp.SwarmTxHash))
 		}
 	}
 
-	return allWalletsFunded
-}
-
-type fundWalletResp struct {
-	wallet                  WalletInfo
-	err                     error
-	transferredNativeAmount *big.Int
-	transferredSwarmAmount  *big.Int
+	return reports
 }
 
 var (
+	ErrInsufficientBudget           = errors.New("insufficient funding wallet balance")
 	ErrFailedFunding                = errors.New("failed funding")
+	ErrFailedFetchingWallet         = errors.New("failed fetching wallet of node")
 	ErrFailedFundingWithSwarmToken  = errors.New("failed funding with swarm token")
 	ErrFailedFundingWithNativeToken = errors.New("failed funding with native token")
 )
@@ -206,48 +460,82 @@ var (
 func fundWalletAsync(
 	ctx context.Context,
 	fundingWallet *wallet.Wallet,
-	minAmounts MinAmounts,
+	policy fundingPolicy,
+	caps spendingCaps,
 	wi WalletInfo,
-) <-chan fundWalletResp {
-	respC := make(chan fundWalletResp, 1)
+	pool *workerPool,
+) <-chan WalletReport {
+	respC := make(chan WalletReport, 1)
+
+	pool.submit(func() {
+		report := WalletReport{
+			Wallet:        wi,
+			BalanceBefore: Amounts{},
+			Transferred:   newAmounts(),
+		}
 
-	go func() {
 		if err := validateChainID(ctx, fundingWallet, wi); err != nil {
-			respC <- fundWalletResp{wallet: wi, err: err}
+			report.Status = WalletStatusFailed
+			report.Err = err
+			respC <- report
+
 			return
 		}
 
-		nativeResp := <-topUpWalletAsync(ctx, wallet.NativeCoinForChain, fundingWallet.Native(), minAmounts.NativeCoin, wi)
-		swarmResp := <-topUpWalletAsync(ctx, wallet.SwarmTokenForChain, fundingWallet.ERC20(), minAmounts.SwarmToken, wi)
+		nativeResp := <-topUpWalletAsync(ctx, wallet.NativeCoinForChain, fundingWallet.Native(), policy.native, caps.native, wi)
+		swarmResp := <-topUpWalletAsync(ctx, wallet.SwarmTokenForChain, fundingWallet.ERC20(), policy.swarm, caps.swarm, wi)
 
-		err := mergeErrors(
-			ErrFailedFunding,
-			mergeErrors(ErrFailedFundingWithNativeToken, nativeResp.err),
-			mergeErrors(ErrFailedFundingWithSwarmToken, swarmResp.err),
-		)
+		report.BalanceBefore = Amounts{NativeCoin: nativeResp.balance, SwarmToken: swarmResp.balance}
+		report.NativeTxHash = nativeResp.txHash
+		report.SwarmTxHash = swarmResp.txHash
+		report.NativeErr = mergeErrors(ErrFailedFundingWithNativeToken, nativeResp.err)
+		report.SwarmErr = mergeErrors(ErrFailedFundingWithSwarmToken, swarmResp.err)
+		report.Err = mergeErrors(ErrFailedFunding, report.NativeErr, report.SwarmErr)
 
-		respC <- fundWalletResp{
-			wallet:                  wi,
-			err:                     err,
-			transferredNativeAmount: nativeResp.transferredAmount,
-			transferredSwarmAmount:  swarmResp.transferredAmount,
+		if nativeResp.transferredAmount != nil {
+			report.Transferred.NativeCoin = nativeResp.transferredAmount
 		}
-	}()
+
+		if swarmResp.transferredAmount != nil {
+			report.Transferred.SwarmToken = swarmResp.transferredAmount
+		}
+
+		switch {
+		case report.Err != nil && (nativeResp.transferredAmount != nil || swarmResp.transferredAmount != nil):
+			// the token which was transferred is reported, the error is kept
+			// on the token which was not
+			report.Status = WalletStatusPartiallyFunded
+		case errors.Is(report.Err, ErrSpendingCapExceeded):
+			report.Status = WalletStatusSkipped
+		case report.Err != nil:
+			report.Status = WalletStatusFailed
+		case nativeResp.transferredAmount == nil && swarmResp.transferredAmount == nil:
+			report.Status = WalletStatusAlreadyFunded
+		default:
+			report.Status = WalletStatusFunded
+		}
+
+		respC <- report
+	})
 
 	return respC
 }
 
 func mergeErrors(main error, errs ...error) error {
-	var errorMsg []string
+	var (
+		verbs   []string
+		wrapped []any
+	)
 
 	for _, err := range errs {
 		if err != nil {
-			errorMsg = append(errorMsg, err.Error())
+			verbs = append(verbs, "%w")
+			wrapped = append(wrapped, err)
 		}
 	}
 
-	if len(errorMsg) > 0 {
-		return fmt.Errorf("%w, reason: %s", main, strings.Join(errorMsg, ", "))
+	if len(wrapped) > 0 {
+		return fmt.Errorf("%w, reason: "+strings.Join(verbs, ", "), append([]any{main}, wrapped...)...)
 	}
 
 	return nil
@@ -265,25 +553,23 @@ func validateChainID(ctx context.Context, fundingWallet *wallet.Wallet, wi Walle
 
 type topUpResp struct {
 	err               error
+	balance           *big.Int
 	transferredAmount *big.Int
+	txHash            common.Hash
 }
 
 func topUpWalletAsync(
 	ctx context.Context,
 	tokenInfoGetter wallet.TokenInfoGetterFn,
 	fundingWallet wallet.TokenWallet,
-	minAmount float64,
+	policy topUpPolicy,
+	spendingCap *spendingCap,
 	wi WalletInfo,
 ) <-chan topUpResp {
 	respC := make(chan topUpResp, 1)
 
 	go func() {
-		transferredAmount, err := topUpWallet(ctx, tokenInfoGetter, fundingWallet, minAmount, wi)
-
-		respC <- topUpResp{
-			transferredAmount: transferredAmount,
-			err:               err,
-		}
+		respC <- topUpWallet(ctx, tokenInfoGetter, fundingWallet, policy, spendingCap, wi)
 	}()
 
 	return respC
@@ -293,93 +579,256 @@ func topUpWallet(
 	ctx context.Context,
 	tokenInfoGetter wallet.TokenInfoGetterFn,
 	fundingWallet wallet.TokenWallet,
-	minAmount float64,
+	policy topUpPolicy,
+	spendingCap *spendingCap,
 	wi WalletInfo,
-) (*big.Int, error) {
+) topUpResp {
 	token, err := tokenInfoGetter(wi.ChainID)
 	if err != nil {
-		return nil, err
+		return topUpResp{err: err}
 	}
 
 	if !common.IsHexAddress(wi.Address) {
-		return nil, fmt.Errorf("unexpected wallet address")
+		return topUpResp{err: fmt.Errorf("unexpected wallet address")}
 	}
 
 	address := common.HexToAddress(wi.Address)
 
 	currentBalance, err := fundingWallet.Balance(ctx, address, token)
 	if err != nil {
-		return nil, err
+		return topUpResp{err: err}
 	}
 
-	topUpAmount := calcTopUpAmount(minAmount, currentBalance, token.Decimals)
+	topUpAmount := calcTopUpAmount(policy, currentBalance)
 	if topUpAmount.Cmp(big.NewInt(0)) <= 0 {
 		// Top up is not needed, current balance is sufficient
-		return nil, nil
+		return topUpResp{balance: currentBalance}
 	}
 
-	err = fundingWallet.Transfer(ctx, address, topUpAmount, token)
-	if err != nil {
-		return nil, err
+	// no new transfer is started once the funding is canceled
+	if err := ctx.Err(); err != nil {
+		return topUpResp{balance: currentBalance, err: err}
 	}
 
-	return topUpAmount, nil
-}
-
-func calcTopUpAmount(minVal float64, currAmount *big.Int, decimals int) *big.Int {
-	exp := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	if err := spendingCap.reserve(topUpAmount); err != nil {
+		return topUpResp{balance: currentBalance, err: err}
+	}
 
-	minAmount := big.NewFloat(minVal)
-	minAmount = minAmount.Mul(
-		minAmount,
-		big.NewFloat(0).SetInt(exp),
-	)
+	txHash, err := fundingWallet.Transfer(ctx, address, topUpAmount, token)
+	if err != nil {
+		if txHash == (common.Hash{}) {
+			// transaction was not sent, nothing was spent
+			spendingCap.release(topUpAmount)
+		}
 
-	minAmountInt, _ := minAmount.Int(big.NewInt(0))
+		return topUpResp{balance: currentBalance, txHash: txHash, err: err}
+	}
 
-	return minAmountInt.Sub(minAmountInt, currAmount)
+	return topUpResp{
+		balance:           currentBalance,
+		transferredAmount: topUpAmount,
+		txHash:            txHash,
+	}
 }
 
-func formatAmount(amount *big.Int, decimals int) string {
-	if amount == nil {
-		return "0"
+// calcTopUpAmount returns amount needed to top up current amount to the target
+// amount when it is below the min amount. Result is not positive when top up is
+// not needed. Target amount below the min amount means topping up to the min amount.
+func calcTopUpAmount(policy topUpPolicy, currAmount *big.Int) *big.Int {
+	if currAmount.Cmp(policy.min) >= 0 || policy.target.Cmp(policy.min) <= 0 {
+		return new(big.Int).Sub(policy.min, currAmount)
 	}
 
-	exp := big.NewInt(0).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Int).Sub(policy.target, currAmount)
+}
 
-	a := big.NewFloat(0).SetInt(amount)
-	a.Quo(a, big.NewFloat(0).SetInt(exp))
+func formatTxHash(txHash common.Hash) string {
+	if txHash == (common.Hash{}) {
+		return "-"
+	}
 
-	return a.String()
+	return txHash.Hex()
 }
 
-func makeFundingWallet(ctx context.Context, cfg Config) (*wallet.Wallet, error) {
-	key, err := makeWalletKey(cfg)
+func makeFundingWallet(ctx context.Context, cfg Config, options ...wallet.WalletOptions) (*wallet.Wallet, error) {
+	signer, err := makeWalletSigner(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("making wallet key failed: %w", err)
+		return nil, fmt.Errorf("making wallet signer failed: %w", err)
 	}
 
-	_, err = key.PublicAddress()
+	_, err = signer.PublicAddress()
 	if err != nil {
 		return nil, fmt.Errorf("getting wallet public key failed: %w", err)
 	}
 
 	ethClient, err := makeEthClient(ctx, cfg.ChainNodeEndpoint)
 	if err != nil {
+		if c, ok := signer.(interface{ Close() }); ok {
+			c.Close()
+		}
+
 		return nil, fmt.Errorf("making eth client failed: %w", err)
 	}
 
-	fundingWallet := wallet.New(ethClient, key)
+	options = append([]wallet.WalletOptions{
+		wallet.WithConfirmationsOption(cfg.Confirmations),
+		wallet.WithReceiptTimeoutOption(cfg.ReceiptTimeout),
+	}, options...)
+
+	fundingWallet := wallet.New(ethClient, signer, options...)
 
 	return fundingWallet, nil
 }
 
+var (
+	// ErrNoWalletKey is returned when no source of the funding wallet key is
+	// configured.
+	ErrNoWalletKey = errors.New("wallet key is not set, set one of wallet key, key file, keystore, mnemonic, key source or remote signer")
+	// ErrConflictingWalletKey is returned when more than one source of the
+	// funding wallet key is configured.
+	ErrConflictingWalletKey = errors.New("only one of wallet key, key file, keystore, mnemonic, key source or remote signer can be set")
+)
+
+// makeWalletSigner returns signer of the funding wallet, the remote signer
+// when it is configured, or the wallet key otherwise.
+func makeWalletSigner(ctx context.Context, cfg Config) (wallet.Signer, error) {
+	if cfg.WalletSigner == "" {
+		return makeWalletKey(cfg)
+	}
+
+	if cfg.hasWalletKey() {
+		return nil, ErrConflictingWalletKey
+	}
+
+	var address common.Address
+	if cfg.WalletAddress != "" {
+		if !common.IsHexAddress(cfg.WalletAddress) {
+			return nil, fmt.Errorf("invalid wallet address %q", cfg.WalletAddress)
+		}
+
+		address = common.HexToAddress(cfg.WalletAddress)
+	}
+
+	method := cfg.WalletSignerMethod
+	if method == "" {
+		method = wallet.SignMethodEth
+	}
+
+	return wallet.NewRemoteSigner(ctx, cfg.WalletSigner, method, address)
+}
+
 func makeWalletKey(cfg Config) (wallet.Key, error) {
-	if cfg.WalletKey == "" {
-		return wallet.GenerateKey()
+	source, err := walletKeySource(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return source.Key()
+}
+
+// walletKeySource returns the only source of the funding wallet key
+// configured in cfg.
+func walletKeySource(cfg Config) (wallet.KeySource, error) {
+	var sources []wallet.KeySource
+
+	if cfg.WalletKeySource != nil {
+		sources = append(sources, cfg.WalletKeySource)
+	}
+
+	if cfg.WalletKey != "" {
+		sources = append(sources, wallet.HexKeySource(cfg.WalletKey))
+	}
+
+	if cfg.WalletKeyFile != "" {
+		sources = append(sources, wallet.FileKeySource(cfg.WalletKeyFile))
+	}
+
+	if cfg.WalletKeystore != "" {
+		password, err := walletPassword(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, wallet.KeystoreKeySource(cfg.WalletKeystore, password))
+	}
+
+	if cfg.hasWalletMnemonic() {
+		mnemonic, err := walletMnemonic(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		passphrase, err := walletPassword(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, wallet.MnemonicKeySource(mnemonic, passphrase, cfg.derivationPath(), cfg.WalletIndex))
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, ErrNoWalletKey
+	case 1:
+		return sources[0], nil
+	}
+
+	return nil, ErrConflictingWalletKey
+}
+
+// DeriveAddresses returns addresses of count accounts of the wallet mnemonic,
+// starting with the wallet index, e.g. to fund a fleet of test wallets
+// derived from the same seed.
+func DeriveAddresses(cfg Config, count uint32) ([]common.Address, error) {
+	if !cfg.hasWalletMnemonic() {
+		return nil, errors.New("wallet mnemonic is not set")
+	}
+
+	mnemonic, err := walletMnemonic(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := walletPassword(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hd, err := wallet.NewHDWallet(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return hd.Addresses(cfg.derivationPath(), cfg.WalletIndex, count)
+}
+
+// walletMnemonic returns mnemonic of the wallet, read from the mnemonic file
+// when it is configured.
+func walletMnemonic(cfg Config) (string, error) {
+	if cfg.WalletMnemonic != "" && cfg.WalletMnemonicFile != "" {
+		return "", ErrConflictingWalletKey
+	}
+
+	if cfg.WalletMnemonicFile == "" {
+		return cfg.WalletMnemonic, nil
+	}
+
+	return wallet.ReadMnemonicFile(cfg.WalletMnemonicFile)
+}
+
+// walletPassword returns password of the keystore, or passphrase of the
+// mnemonic, read from the password file when it is configured.
+func walletPassword(cfg Config) (string, error) {
+	if cfg.WalletPasswordFile == "" {
+		return cfg.WalletPassword, nil
+	}
+
+	data, err := os.ReadFile(cfg.WalletPasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("read wallet password file: %w", err)
 	}
 
-	return wallet.Key(cfg.WalletKey), nil
+	return strings.TrimRight(string(data), "\r\n"), nil
 }
 
 func makeEthClient(ctx context.Context, endpoint string) (*ethclient.Client, error) {
@@ -5,12 +5,21 @@
 package funder_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"encoding/pem"
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/ethersphere/node-funder/pkg/funder"
@@ -23,8 +32,8 @@ func Test_Fund(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
-	bc := walletmock.NewBackendClient()
 	key := generateKey(t)
+	bc := newFundedBackendClient(t, key)
 	w := wallet.New(bc, key)
 
 	t.Run("fund addresses - empty", func(t *testing.T) {
@@ -32,10 +41,19 @@ func Test_Fund(t *testing.T) {
 
 		cfg := Config{}
 		nl := fundermock.NewNodeLister(nil)
-		err := Fund(ctx, cfg, nl, w)
+		_, err := Fund(ctx, cfg, nl, w)
 		assert.NoError(t, err)
 	})
 
+	t.Run("no wallet key", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{}
+		nl := fundermock.NewNodeLister(nil)
+		_, err := Fund(ctx, cfg, nl, nil)
+		assert.ErrorIs(t, err, ErrNoWalletKey)
+	})
+
 	t.Run("fund addresses - set", func(t *testing.T) {
 		t.Parallel()
 
@@ -44,7 +62,7 @@ func Test_Fund(t *testing.T) {
 
 			cfg := Config{Addresses: []string{"0x95f8916183f7C7154e49396507F5b0FafA4d8077"}}
 			nl := fundermock.NewNodeLister(nil)
-			err := Fund(ctx, cfg, nl, w)
+			_, err := Fund(ctx, cfg, nl, w)
 			assert.NoError(t, err)
 		})
 
@@ -58,20 +76,325 @@ func Test_Fund(t *testing.T) {
 					"0x95f8916183f7C7154e49396507F5b0FafA4d8072",
 					"0x95f8916183f7C7154e49396507F5b0FafA4d8073",
 				},
-				MinAmounts: MinAmounts{NativeCoin: 3, SwarmToken: 3},
+				MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"},
 			}
 			nl := fundermock.NewNodeLister(nil)
-			err := Fund(ctx, cfg, nl, w)
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 4, report.Totals.Funded)
+		})
+	})
+
+	t.Run("fund addresses - bounded concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		// transfers are in flight from their sending until their receipt,
+		// which is mined on the first query
+		var inFlight, maxInFlight atomic.Int32
+
+		bc := newFundedBackendClient(t, key,
+			walletmock.WithSendTransaction(func(*types.Transaction) error {
+				n := inFlight.Add(1)
+
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+
+				return nil
+			}),
+			walletmock.WithTransactionReceipt(func(common.Hash) {
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+			}),
+		)
+		w := wallet.New(bc, key)
+
+		addresses := make([]string, 6)
+		for i := range addresses {
+			addresses[i] = common.BigToAddress(big.NewInt(int64(i + 1))).Hex()
+		}
+
+		cfg := Config{Addresses: addresses, MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"}}
+		nl := fundermock.NewNodeLister(nil)
+		report, err := Fund(ctx, cfg, nl, w, WithConcurrencyOption(2))
+		assert.NoError(t, err)
+		assert.Equal(t, 6, report.Totals.Funded)
+		assert.Equal(t, int32(2), maxInFlight.Load())
+	})
+
+	t.Run("fund addresses - transfer not completed", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			Addresses:  []string{"0x95f8916183f7C7154e49396507F5b0FafA4d8077"},
+			MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"},
+		}
+		nl := fundermock.NewNodeLister(nil)
+
+		t.Run("reverted", func(t *testing.T) {
+			t.Parallel()
+
+			bc := newFundedBackendClient(t, key, walletmock.WithReceiptOutcome(walletmock.ReceiptReverted))
+			w := wallet.New(bc, key)
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorContains(t, err, "funding all wallets failed")
+			assert.ErrorIs(t, report.Wallets[0].NativeErr, wallet.ErrTransactionReverted)
+		})
+
+		t.Run("pending", func(t *testing.T) {
+			t.Parallel()
+
+			bc := newFundedBackendClient(t, key, walletmock.WithReceiptOutcome(walletmock.ReceiptPending))
+			w := wallet.New(bc, key,
+				wallet.WithReceiptTimeoutOption(50*time.Millisecond),
+				wallet.WithPollIntervalOption(10*time.Millisecond),
+			)
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorContains(t, err, "funding all wallets failed")
+			assert.ErrorIs(t, report.Wallets[0].NativeErr, wallet.ErrTransactionPending)
+		})
+
+		t.Run("mined with confirmations", func(t *testing.T) {
+			t.Parallel()
+
+			w := wallet.New(bc, key, wallet.WithConfirmationsOption(5))
+			_, err := Fund(ctx, cfg, nl, w)
 			assert.NoError(t, err)
 		})
 	})
 
+	t.Run("fund addresses - insufficient budget", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			Addresses: []string{
+				"0x95f8916183f7C7154e49396507F5b0FafA4d8077",
+				"0x95f8916183f7C7154e49396507F5b0FafA4d8071",
+				"0x95f8916183f7C7154e49396507F5b0FafA4d8072",
+			},
+			MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"},
+		}
+		nl := fundermock.NewNodeLister(nil)
+
+		// funding wallet holds 5 xDAI, enough for two wallets only
+		addr, err := key.PublicAddress()
+		assert.NoError(t, err)
+		bc := walletmock.NewBackendClient(walletmock.WithBalance(addr, toBigInt("5000000000000000000"), toBigInt("1000000000000000000")))
+		w := wallet.New(bc, key)
+
+		t.Run("abort", func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorIs(t, err, ErrInsufficientBudget)
+		})
+
+		t.Run("prioritized", func(t *testing.T) {
+			t.Parallel()
+
+			cfg := cfg
+			cfg.PrioritizedFunding = true
+			_, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+		})
+
+		t.Run("swarm token", func(t *testing.T) {
+			t.Parallel()
+
+			// funding wallet holds enough xDAI, but 0.5 xBZZ only
+			var sent atomic.Int32
+			bc := walletmock.NewBackendClient(
+				walletmock.WithBalance(addr, toBigInt("1000000000000000000000"), toBigInt("5000000000000000")),
+				walletmock.WithSendTransaction(func(*types.Transaction) error {
+					sent.Add(1)
+					return nil
+				}),
+			)
+			w := wallet.New(bc, key)
+
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorIs(t, err, ErrInsufficientBudget)
+			assert.Empty(t, report.Wallets)
+			assert.Zero(t, sent.Load())
+		})
+	})
+
+	t.Run("fund addresses - not planned", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			Addresses:  []string{"0x95f8916183f7C7154e49396507F5b0FafA4d8077", "not-an-address"},
+			MinAmounts: MinAmounts{NativeCoin: "3"},
+		}
+		nl := fundermock.NewNodeLister(nil)
+
+		report, err := Fund(ctx, cfg, nl, w)
+		assert.ErrorIs(t, err, ErrFailedFunding)
+		assert.Empty(t, report.Wallets)
+
+		cfg.PrioritizedFunding = true
+		report, err = Fund(ctx, cfg, nl, w)
+		assert.Error(t, err)
+		assert.Equal(t, 1, report.Totals.Funded)
+		assert.Equal(t, 1, report.Totals.Failed)
+	})
+
+	t.Run("fund addresses - spending caps", func(t *testing.T) {
+		t.Parallel()
+
+		addresses := []string{
+			"0x95f8916183f7C7154e49396507F5b0FafA4d8077",
+			"0x95f8916183f7C7154e49396507F5b0FafA4d8071",
+			"0x95f8916183f7C7154e49396507F5b0FafA4d8072",
+		}
+		nl := fundermock.NewNodeLister(nil)
+
+		// every wallet needs 2 xDAI and 0.94252237824 xBZZ
+		tests := []struct {
+			name         string
+			maxTotal     MaxAmounts
+			maxPerWallet MaxAmounts
+			wantErr      bool
+		}{
+			{name: "within caps", maxTotal: MaxAmounts{NativeCoin: "6", SwarmToken: "3"}, maxPerWallet: MaxAmounts{NativeCoin: "2", SwarmToken: "1"}},
+			{name: "per wallet native cap", maxPerWallet: MaxAmounts{NativeCoin: "1"}, wantErr: true},
+			{name: "per wallet swarm cap", maxPerWallet: MaxAmounts{SwarmToken: "0.5"}, wantErr: true},
+			{name: "total native cap", maxTotal: MaxAmounts{NativeCoin: "5"}, wantErr: true},
+			{name: "total swarm cap", maxTotal: MaxAmounts{SwarmToken: "2"}, wantErr: true},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				cfg := Config{
+					Addresses:    addresses,
+					MinAmounts:   MinAmounts{NativeCoin: "3", SwarmToken: "3"},
+					MaxTotal:     tc.maxTotal,
+					MaxPerWallet: tc.maxPerWallet,
+				}
+				_, err := Fund(ctx, cfg, nl, w)
+				if tc.wantErr {
+					assert.ErrorIs(t, err, ErrSpendingCapExceeded)
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+
+		t.Run("native transferred before swarm cap", func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{
+				Addresses:    addresses,
+				MinAmounts:   MinAmounts{NativeCoin: "3", SwarmToken: "3"},
+				MaxPerWallet: MaxAmounts{SwarmToken: "0.5"},
+			}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorIs(t, err, ErrSpendingCapExceeded)
+			assert.Equal(t, 3, report.Totals.PartiallyFunded)
+			assert.Zero(t, report.Totals.Skipped)
+
+			for _, wr := range report.Wallets {
+				assert.Equal(t, WalletStatusPartiallyFunded, wr.Status)
+				assert.Equal(t, "2000000000000000000", wr.Transferred.NativeCoin.String())
+				assert.NoError(t, wr.NativeErr)
+				assert.ErrorIs(t, wr.SwarmErr, ErrSpendingCapExceeded)
+			}
+		})
+	})
+
+	t.Run("fund addresses - report", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			Addresses: []string{
+				"0x95f8916183f7C7154e49396507F5b0FafA4d8077",
+				"0x95f8916183f7C7154e49396507F5b0FafA4d8071",
+			},
+			MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "1"},
+		}
+		nl := fundermock.NewNodeLister(nil)
+
+		t.Run("funded", func(t *testing.T) {
+			t.Parallel()
+
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Len(t, report.Wallets, 2)
+			assert.Equal(t, 2, report.Totals.Funded)
+			assert.Equal(t, "4000000000000000000", report.Totals.Transferred.NativeCoin.String())
+			assert.Equal(t, "0", report.Totals.Transferred.SwarmToken.String())
+
+			for _, wr := range report.Wallets {
+				assert.Equal(t, WalletStatusFunded, wr.Status)
+				assert.Equal(t, "1000000000000000000", wr.BalanceBefore.NativeCoin.String())
+				assert.NotEqual(t, common.Hash{}, wr.NativeTxHash)
+				assert.Equal(t, common.Hash{}, wr.SwarmTxHash)
+			}
+
+			var buf bytes.Buffer
+			assert.NoError(t, report.WriteJSON(&buf))
+
+			var doc struct {
+				FundingAddress string `json:"fundingAddress"`
+				NativeCoin     string `json:"nativeCoin"`
+				Wallets        []struct {
+					Address     string `json:"address"`
+					ChainID     int64  `json:"chainID"`
+					Status      string `json:"status"`
+					Transferred struct {
+						NativeCoin string `json:"nativeCoin"`
+					} `json:"transferred"`
+					TxHashes struct {
+						NativeCoin string `json:"nativeCoin"`
+						SwarmToken string `json:"swarmToken"`
+					} `json:"txHashes"`
+				} `json:"wallets"`
+				Totals struct {
+					Funded int `json:"funded"`
+				} `json:"totals"`
+			}
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+			assert.Equal(t, "xDAI", doc.NativeCoin)
+			assert.Equal(t, 2, doc.Totals.Funded)
+			assert.Equal(t, cfg.Addresses[0], doc.Wallets[0].Address)
+			assert.Equal(t, int64(100), doc.Wallets[0].ChainID)
+			assert.Equal(t, "funded", doc.Wallets[0].Status)
+			assert.Equal(t, "2000000000000000000", doc.Wallets[0].Transferred.NativeCoin)
+			assert.Equal(t, report.Wallets[0].NativeTxHash.Hex(), doc.Wallets[0].TxHashes.NativeCoin)
+			assert.Empty(t, doc.Wallets[0].TxHashes.SwarmToken)
+		})
+
+		t.Run("failed", func(t *testing.T) {
+			t.Parallel()
+
+			bc := newFundedBackendClient(t, key, walletmock.WithReceiptOutcome(walletmock.ReceiptReverted))
+			w := wallet.New(bc, key)
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.Error(t, err)
+			assert.Equal(t, 2, report.Totals.Failed)
+
+			for _, wr := range report.Wallets {
+				assert.Equal(t, WalletStatusFailed, wr.Status)
+				assert.ErrorIs(t, wr.Err, ErrFailedFunding)
+				assert.ErrorIs(t, wr.NativeErr, ErrFailedFundingWithNativeToken)
+				assert.ErrorIs(t, wr.NativeErr, wallet.ErrTransactionReverted)
+				assert.NoError(t, wr.SwarmErr)
+				assert.NotEqual(t, common.Hash{}, wr.NativeTxHash)
+			}
+		})
+	})
+
 	t.Run("fund namespace - empty", func(t *testing.T) {
 		t.Parallel()
 
 		nl := fundermock.NewNodeLister(nil)
 		cfg := Config{Namespace: "swarm"}
-		err := Fund(ctx, cfg, nl, w)
+		_, err := Fund(ctx, cfg, nl, w)
 		assert.NoError(t, err)
 	})
 
@@ -80,7 +403,7 @@ func Test_Fund(t *testing.T) {
 
 		nl := fundermock.NewNodeLister([]NodeInfo{{Address: "addr"}})
 		cfg := Config{Namespace: "swarm"}
-		err := Fund(ctx, cfg, nl, w)
+		_, err := Fund(ctx, cfg, nl, w)
 		assert.NoError(t, err)
 	})
 
@@ -97,63 +420,311 @@ func Test_Fund(t *testing.T) {
 			assert.NoError(t, err)
 		}))
 		t.Cleanup(server.Close)
-		nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL}})
+		nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL, Name: "bee-0"}})
 
 		t.Run("already funded (0,0)", func(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{Namespace: "swarm"}
-			err := Fund(ctx, cfg, nl, w)
+			_, err := Fund(ctx, cfg, nl, w)
 			assert.NoError(t, err)
 		})
 
 		t.Run("already funded (1,1)", func(t *testing.T) {
 			t.Parallel()
 
-			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: 1, SwarmToken: 1}}
-			err := Fund(ctx, cfg, nl, w)
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "1", SwarmToken: "1"}}
+			_, err := Fund(ctx, cfg, nl, w)
 			assert.NoError(t, err)
 		})
 
 		t.Run("not funded (3,3)", func(t *testing.T) {
 			t.Parallel()
 
-			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: 3, SwarmToken: 3}}
-			err := Fund(ctx, cfg, nl, w)
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+		})
+
+		t.Run("multiple namespaces", func(t *testing.T) {
+			t.Parallel()
+
+			server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, err := w.Write([]byte(`{"walletAddress": "0x4C4E453E72aF9939A27cac5a09ba583d72c4DfF0", "chainID": 100}`))
+				assert.NoError(t, err)
+			}))
+			t.Cleanup(server2.Close)
+
+			nl := namespaceNodeLister{
+				"swarm":   {{Address: server.URL, Name: "bee-0"}},
+				"swarm-2": {{Address: server2.URL, Name: "bee-0"}},
+			}
+
+			cfg := Config{Namespaces: []string{"swarm", "swarm-2", "swarm"}, MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, report.Totals.Funded)
+			assert.Len(t, report.Namespaces, 2)
+			assert.Equal(t, 1, report.Namespaces["swarm"].Funded)
+			assert.Equal(t, 1, report.Namespaces["swarm-2"].Funded)
+			assert.Equal(t, "2000000000000000000", report.Namespaces["swarm-2"].Transferred.NativeCoin.String())
+
+			var buf bytes.Buffer
+			assert.NoError(t, report.WriteJSON(&buf))
+			assert.Contains(t, buf.String(), `"namespace": "swarm-2"`)
+		})
+
+		t.Run("same wallet in multiple namespaces", func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{Namespaces: []string{"swarm", "swarm-2"}, MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Wallets)
+			assert.Equal(t, 1, report.Totals.Funded)
+			assert.Len(t, report.Namespaces, 1)
+		})
+
+		t.Run("all namespaces", func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{AllNamespaces: true, MinAmounts: MinAmounts{NativeCoin: "3"}}
+			_, err := Fund(ctx, cfg, nl, w)
+			assert.ErrorIs(t, err, ErrSelectorRequired)
+
+			cfg.FieldSelector = "status.phase=Running"
+			_, err = Fund(ctx, cfg, nl, w)
+			assert.ErrorIs(t, err, ErrSelectorRequired)
+
+			cfg.LabelSelector = "app=bee"
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+		})
+
+		t.Run("name not matching without selector", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL, Name: "node-0"}})
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Totals.Wallets)
+		})
+
+		t.Run("name not matching with selector", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL, Name: "node-0"}})
+			cfg := Config{Namespace: "swarm", LabelSelector: "app=bee", MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+		})
+
+		t.Run("not ready node skipped", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{
+				{Address: server.URL, Name: "bee-0"},
+				{Name: "bee-1", Namespace: "swarm", SkipReason: "pod is Pending"},
+			})
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
 			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+			assert.Equal(t, []SkippedNode{{Name: "bee-1", Namespace: "swarm", Reason: "pod is Pending"}}, report.SkippedNodes)
+
+			var buf bytes.Buffer
+			assert.NoError(t, report.WriteJSON(&buf))
+			assert.Contains(t, buf.String(), `"reason": "pod is Pending"`)
+		})
+
+		t.Run("https with CA bundle", func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`))
+				assert.NoError(t, err)
+			}))
+			t.Cleanup(server.Close)
+			nl := fundermock.NewNodeLister([]NodeInfo{{Address: server.URL, Name: "bee-0"}})
+
+			// server certificate is not trusted without the CA bundle
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "3"}}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.Error(t, err)
+			assert.Equal(t, 1, report.Totals.Failed)
+			assert.ErrorIs(t, report.Wallets[0].Err, ErrFailedFetchingWallet)
+
+			caFile := filepath.Join(t.TempDir(), "ca.pem")
+			caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			assert.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+			cfg.APICACertFile = caFile
+			report, err = Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+		})
+
+		t.Run("wait for node to become ready", func(t *testing.T) {
+			t.Parallel()
+
+			nl := &readyAfterLister{node: NodeInfo{Address: server.URL, Name: "bee-0"}, calls: 2}
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{NativeCoin: "3"}, ReadyTimeout: time.Second}
+			report, err := Fund(ctx, cfg, nl, w)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Funded)
+			assert.Empty(t, report.SkippedNodes)
 		})
 	})
 }
 
+// readyAfterLister lists a node which is not ready for the first calls.
+type readyAfterLister struct {
+	node  NodeInfo
+	calls int32
+	count atomic.Int32
+}
+
+func (nl *readyAfterLister) List(context.Context, string) ([]NodeInfo, error) {
+	node := nl.node
+	if nl.count.Add(1) <= nl.calls {
+		node.SkipReason = "container bee is not ready"
+	}
+
+	return []NodeInfo{node}, nil
+}
+
 func Test_CalcTopUpAmount(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		min           float64
+		min           string
+		target        string
 		currAmount    string
 		tokenDecimals int
 		expected      string
 	}{
 		{
-			min:           2.4,
+			min:           "2.4",
 			currAmount:    "1000000000000000000",
 			tokenDecimals: 18,
 			expected:      "1400000000000000000",
 		},
 		{
-			min:           2.4,
+			min:           "2.4",
 			currAmount:    "3000000000000000000",
 			tokenDecimals: 18,
 			expected:      "-600000000000000000",
 		},
+		{
+			// below threshold, topped up to target
+			min:           "0.2",
+			target:        "1",
+			currAmount:    "100000000000000000",
+			tokenDecimals: 18,
+			expected:      "900000000000000000",
+		},
+		{
+			// above threshold, below target, no dust transfer
+			min:           "0.2",
+			target:        "1",
+			currAmount:    "300000000000000000",
+			tokenDecimals: 18,
+			expected:      "-100000000000000000",
+		},
+		{
+			// exactly at threshold
+			min:           "0.2",
+			target:        "1",
+			currAmount:    "200000000000000000",
+			tokenDecimals: 18,
+			expected:      "0",
+		},
+		{
+			// target below threshold, topped up to threshold
+			min:           "2",
+			target:        "1",
+			currAmount:    "1000000000000000000",
+			tokenDecimals: 18,
+			expected:      "1000000000000000000",
+		},
+		{
+			min:           "5",
+			target:        "10",
+			currAmount:    "20000000000000000",
+			tokenDecimals: 16,
+			expected:      "80000000000000000",
+		},
+		{
+			// no float rounding artifacts
+			min:           "0.1",
+			currAmount:    "0",
+			tokenDecimals: 18,
+			expected:      "100000000000000000",
+		},
+		{
+			min:           "0.3",
+			target:        "0.7",
+			currAmount:    "100000000000000000",
+			tokenDecimals: 18,
+			expected:      "600000000000000000",
+		},
 	}
 
 	for _, tc := range tests {
-		got := CalcTopUpAmount(tc.min, toBigInt(tc.currAmount), tc.tokenDecimals)
+		got, err := CalcTopUpAmount(tc.min, tc.target, toBigInt(tc.currAmount), wallet.Token{Decimals: tc.tokenDecimals})
+		assert.NoError(t, err)
 		assert.Equal(t, tc.expected, got.String())
 	}
 }
 
+func Test_ParseAmount(t *testing.T) {
+	t.Parallel()
+
+	native := wallet.Token{Decimals: 18, BaseUnit: wallet.NativeCoinBaseUnit}
+	swarm := wallet.Token{Decimals: 16, BaseUnit: wallet.SwarmTokenBaseUnit}
+
+	tests := []struct {
+		value    string
+		token    wallet.Token
+		expected string
+		wantErr  bool
+	}{
+		{value: "", token: native, expected: "0"},
+		{value: "0", token: native, expected: "0"},
+		{value: "1", token: native, expected: "1000000000000000000"},
+		{value: "0.1", token: native, expected: "100000000000000000"},
+		{value: ".5", token: native, expected: "500000000000000000"},
+		{value: "2.", token: native, expected: "2000000000000000000"},
+		{value: "0.000000000000000001", token: native, expected: "1"},
+		{value: "1.2345678901234567", token: swarm, expected: "12345678901234567"},
+		{value: "1.23456789012345670", token: swarm, expected: "12345678901234567"},
+		{value: "123wei", token: native, expected: "123"},
+		{value: "123 plur", token: swarm, expected: "123"},
+		{value: "1.23456789012345678", token: swarm, wantErr: true},
+		{value: "123plur", token: native, wantErr: true},
+		{value: "1.5wei", token: native, wantErr: true},
+		{value: "-1", token: native, wantErr: true},
+		{value: "1e18", token: native, wantErr: true},
+		{value: ".", token: native, wantErr: true},
+		{value: "abc", token: native, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseAmount(tc.value, tc.token)
+		if tc.wantErr {
+			assert.Error(t, err, tc.value)
+			continue
+		}
+
+		assert.NoError(t, err, tc.value)
+		assert.Equal(t, tc.expected, got.String(), tc.value)
+	}
+}
+
 func Test_FormatAmount(t *testing.T) {
 	t.Parallel()
 
@@ -163,6 +734,10 @@ func Test_FormatAmount(t *testing.T) {
 	assert.Equal(t, "10", FormatAmount(big.NewInt(1000), 2))
 	assert.Equal(t, "10.1", FormatAmount(big.NewInt(1010), 2))
 	assert.Equal(t, "10.01", FormatAmount(big.NewInt(1001), 2))
+	assert.Equal(t, "0.01", FormatAmount(big.NewInt(1), 2))
+	assert.Equal(t, "-10.01", FormatAmount(big.NewInt(-1001), 2))
+	assert.Equal(t, "0.1", FormatAmount(toBigInt("100000000000000000"), 18))
+	assert.Equal(t, "1.2345678901234567", FormatAmount(toBigInt("12345678901234567"), 16))
 }
 
 func toBigInt(val string) *big.Int {
@@ -172,6 +747,26 @@ func toBigInt(val string) *big.Int {
 	return bi
 }
 
+// newFundedBackendClient returns backend client where the wallet of the key
+// holds enough funds to fund wallets in tests.
+func newFundedBackendClient(t *testing.T, key wallet.Key, opts ...walletmock.Option) wallet.BackendClient {
+	t.Helper()
+
+	addr, err := key.PublicAddress()
+	assert.NoError(t, err)
+
+	opts = append(opts, walletmock.WithBalance(addr, toBigInt("1000000000000000000000"), toBigInt("1000000000000000000")))
+
+	return walletmock.NewBackendClient(opts...)
+}
+
+// namespaceNodeLister lists nodes by namespace.
+type namespaceNodeLister map[string][]NodeInfo
+
+func (nl namespaceNodeLister) List(_ context.Context, namespace string) ([]NodeInfo, error) {
+	return nl[namespace], nil
+}
+
 func generateKey(t *testing.T) wallet.Key {
 	t.Helper()
 
@@ -6,31 +6,80 @@ package funder
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
-	"io"
 	"net/http"
+	"os"
+
+	"github.com/ethersphere/node-funder/pkg/beeclient"
 )
 
-func sendHTTPRequest(ctx context.Context, method, endpoint string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+// apiClient makes clients of bee API of nodes, sharing HTTP client and
+// credentials.
+type apiClient struct {
+	client  *http.Client
+	auth    *apiAuth
+	options []beeclient.ClientOptions
+}
+
+func newAPIClient(cfg Config) (*apiClient, error) {
+	client, err := newHTTPClient(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	auth, err := newAPIAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{
+		client: client,
+		auth:   auth,
+		options: []beeclient.ClientOptions{
+			beeclient.WithHTTPClientOption(client),
+			beeclient.WithTimeoutOption(cfg.APITimeout),
+			beeclient.WithRetriesOption(cfg.APIRetries),
+		},
+	}, nil
+}
+
+// node returns client of bee API of the node.
+func (c *apiClient) node(node NodeInfo) *beeclient.Client {
+	authorizer := beeclient.AuthorizerFunc(func(ctx context.Context, req *http.Request) error {
+		return c.auth.authorize(ctx, req, node.Namespace)
+	})
 
-	client := &http.Client{}
+	return beeclient.New(node.Address, append(c.options, beeclient.WithAuthorizerOption(authorizer))...)
+}
 
-	resp, err := client.Do(req)
+// newHTTPClient returns client for bee API requests. When CA bundle is
+// configured, its CAs are trusted in addition to system CAs.
+func newHTTPClient(cfg Config) (*http.Client, error) {
+	if cfg.APICACertFile == "" {
+		return &http.Client{}, nil
+	}
+
+	pem, err := os.ReadFile(cfg.APICACertFile)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("read CA bundle: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed: status code %d", resp.StatusCode)
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.APICACertFile)
 	}
 
-	defer resp.Body.Close()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
 
-	return io.ReadAll(resp.Body)
+	return &http.Client{Transport: transport}, nil
 }
@@ -0,0 +1,101 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Inventory lists bee nodes running outside of Kubernetes. It is read from a
+// YAML or JSON file, e.g.:
+//
+//	nodes:
+//	  - name: bee-0
+//	    address: http://localhost:1633
+//	  - name: bee-1
+//	    address: http://localhost:1733
+//	    namespace: lab
+type Inventory struct {
+	Nodes []InventoryNode `json:"nodes"`
+}
+
+// InventoryNode is a bee node of the inventory.
+type InventoryNode struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`             // base URL of bee API
+	Namespace string `json:"namespace,omitempty"` // optional group of nodes, selected by namespace
+}
+
+// ReadInventory reads and validates the inventory file.
+func ReadInventory(path string) (Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("read inventory: %w", err)
+	}
+
+	var inv Inventory
+	if err := yaml.UnmarshalStrict(data, &inv); err != nil {
+		return Inventory{}, fmt.Errorf("parse inventory %s: %w", path, err)
+	}
+
+	names := make(map[string]struct{}, len(inv.Nodes))
+
+	for i, n := range inv.Nodes {
+		if n.Name == "" {
+			return Inventory{}, fmt.Errorf("inventory node %d: name is not set", i)
+		}
+
+		if u, err := url.Parse(n.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Inventory{}, fmt.Errorf("inventory node %s: invalid address %q", n.Name, n.Address)
+		}
+
+		key := NodeInfo{Name: n.Name, Namespace: n.Namespace}.key()
+		if _, ok := names[key]; ok {
+			return Inventory{}, fmt.Errorf("inventory node %s: duplicate name", key)
+		}
+
+		names[key] = struct{}{}
+	}
+
+	return inv, nil
+}
+
+// inventoryLister is NodeLister of nodes in the inventory.
+type inventoryLister struct {
+	inventory Inventory
+}
+
+func newInventoryLister(path string) (NodeLister, error) {
+	inv, err := ReadInventory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventoryLister{inventory: inv}, nil
+}
+
+// List returns nodes of the namespace, or all nodes when namespace is empty.
+func (l *inventoryLister) List(_ context.Context, namespace string) ([]NodeInfo, error) {
+	result := make([]NodeInfo, 0, len(l.inventory.Nodes))
+
+	for _, n := range l.inventory.Nodes {
+		if namespace != "" && n.Namespace != namespace {
+			continue
+		}
+
+		result = append(result, NodeInfo{
+			Name:      n.Name,
+			Address:   n.Address,
+			Namespace: n.Namespace,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+func Test_Inventory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := generateKey(t)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+
+	newServer := func(walletAddress string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var body string
+			switch req.URL.Path {
+			case "/wallet":
+				body = `{"walletAddress": "` + walletAddress + `", "chainID": 100}`
+			case "/stake":
+				body = `{"stakedAmount": "0"}`
+			}
+
+			_, err := w.Write([]byte(body))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	server := newServer("0x95f8916183f7C7154e49396507F5b0FafA4d8077")
+	server2 := newServer("0x4C4E453E72aF9939A27cac5a09ba583d72c4DfF0")
+
+	writeInventory := func(t *testing.T, content string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "inventory.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	inventory := writeInventory(t, fmt.Sprintf(`
+nodes:
+  - name: node-0
+    address: %[1]s
+  - name: node-1
+    address: %[2]s/
+    namespace: lab
+`, server.URL, server2.URL))
+
+	t.Run("read", func(t *testing.T) {
+		t.Parallel()
+
+		inv, err := ReadInventory(inventory)
+		assert.NoError(t, err)
+		assert.Equal(t, []InventoryNode{
+			{Name: "node-0", Address: server.URL},
+			{Name: "node-1", Address: server2.URL + "/", Namespace: "lab"},
+		}, inv.Nodes)
+	})
+
+	t.Run("fund", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Inventory: inventory, MinAmounts: MinAmounts{NativeCoin: "3"}}
+		report, err := Fund(ctx, cfg, nil, w)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Totals.Funded)
+
+		// nodes are selected by namespace
+		cfg.Namespace = "lab"
+		report, err = Fund(ctx, cfg, nil, w)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Totals.Funded)
+		assert.Contains(t, report.Wallets[0].Wallet.Name, "node-1")
+	})
+
+	t.Run("fund with addresses", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Inventory: inventory, Addresses: []string{"0x95f8916183f7C7154e49396507F5b0FafA4d8071"}}
+		_, err := Fund(ctx, cfg, nil, w)
+		assert.ErrorIs(t, err, ErrInventoryWithAddresses)
+	})
+
+	t.Run("stake", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Inventory: inventory, MinAmounts: MinAmounts{SwarmToken: "1"}}
+		report, err := Stake(ctx, cfg, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Totals.Staked)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			content string
+		}{
+			{name: "unknown field", content: "nodes:\n  - name: bee-0\n    url: http://localhost:1633\n"},
+			{name: "missing name", content: "nodes:\n  - address: http://localhost:1633\n"},
+			{name: "invalid address", content: "nodes:\n  - name: bee-0\n    address: localhost:1633\n"},
+			{name: "duplicate name", content: "nodes:\n  - name: bee-0\n    address: http://a:1633\n  - name: bee-0\n    address: http://b:1633\n"},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := ReadInventory(writeInventory(t, tc.content))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+)
+
+func Test_MakeWalletKey(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+	dir := t.TempDir()
+
+	keyFile := filepath.Join(dir, "key")
+	assert.NoError(t, os.WriteFile(keyFile, []byte("0x"+string(key)+"\n"), 0o600))
+
+	privateKey, err := key.PrivateECDSA()
+	assert.NoError(t, err)
+	address, err := key.PublicAddress()
+	assert.NoError(t, err)
+	// cheapest scrypt parameters keep the test fast, decryption reads them
+	// from the keystore
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    address,
+		PrivateKey: privateKey,
+	}, "pass word", 2, 1)
+	assert.NoError(t, err)
+
+	keystoreFile := filepath.Join(dir, "keystore.json")
+	assert.NoError(t, os.WriteFile(keystoreFile, keyJSON, 0o600))
+
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("pass word\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr error
+	}{
+		{name: "key", cfg: Config{WalletKey: string(key)}},
+		{name: "key file", cfg: Config{WalletKeyFile: keyFile}},
+		{name: "keystore", cfg: Config{WalletKeystore: keystoreFile, WalletPassword: "pass word"}},
+		{name: "keystore password file", cfg: Config{WalletKeystore: keystoreFile, WalletPasswordFile: passwordFile}},
+		{name: "keystore wrong password", cfg: Config{WalletKeystore: keystoreFile, WalletPassword: "password"}, wantErr: keystore.ErrDecrypt},
+		{name: "key source", cfg: Config{WalletKeySource: wallet.HexKeySource("0x" + string(key))}},
+		{name: "conflicting", cfg: Config{WalletKey: string(key), WalletKeyFile: keyFile}, wantErr: ErrConflictingWalletKey},
+		{name: "conflicting key source", cfg: Config{WalletKey: string(key), WalletKeySource: wallet.GeneratedKeySource()}, wantErr: ErrConflictingWalletKey},
+		{name: "none", cfg: Config{}, wantErr: ErrNoWalletKey},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MakeWalletKey(tc.cfg)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, key, got)
+		})
+	}
+
+	t.Run("conflicting remote signer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := MakeWalletSigner(context.Background(), Config{WalletSigner: "http://localhost", WalletKey: string(key)})
+		assert.ErrorIs(t, err, ErrConflictingWalletKey)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := MakeWalletKey(Config{WalletKeySource: wallet.GeneratedKeySource()})
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, got)
+
+		_, err = got.PublicAddress()
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := MakeWalletKey(Config{WalletKey: "not a key"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid key file", func(t *testing.T) {
+		t.Parallel()
+
+		invalid := filepath.Join(t.TempDir(), "key")
+		assert.NoError(t, os.WriteFile(invalid, []byte("not a key"), 0o600))
+
+		_, err := MakeWalletKey(Config{WalletKeyFile: invalid})
+		assert.Error(t, err)
+	})
+}
+
+func Test_MakeWalletKey_env(t *testing.T) {
+	key := generateKey(t)
+
+	t.Setenv("TEST_FUNDER_WALLET_KEY", "0x"+string(key))
+
+	got, err := MakeWalletKey(Config{WalletKeySource: wallet.EnvKeySource("TEST_FUNDER_WALLET_KEY")})
+	assert.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	_, err = MakeWalletKey(Config{WalletKeySource: wallet.EnvKeySource("TEST_FUNDER_WALLET_KEY_UNSET")})
+	assert.ErrorContains(t, err, "TEST_FUNDER_WALLET_KEY_UNSET is not set")
+}
+
+// testMnemonic is mnemonic of Hardhat and Anvil development accounts.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func Test_DeriveAddresses(t *testing.T) {
+	t.Parallel()
+
+	want := []common.Address{
+		common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"),
+		common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"),
+		common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+	}
+
+	mnemonicFile := filepath.Join(t.TempDir(), "mnemonic")
+	assert.NoError(t, os.WriteFile(mnemonicFile, []byte(testMnemonic+"\n"), 0o600))
+
+	got, err := DeriveAddresses(Config{WalletMnemonicFile: mnemonicFile}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletIndex: 1}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, want[1:], got)
+
+	key, err := MakeWalletKey(Config{WalletMnemonic: testMnemonic, WalletIndex: 2})
+	assert.NoError(t, err)
+	address, err := key.PublicAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, want[2], address)
+
+	got, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletPassword: "passphrase"}, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, want[0], got[0])
+
+	got, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletDerivationPath: "m/44'/60'/1'/0"}, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, want[0], got[0])
+
+	_, err = DeriveAddresses(Config{WalletMnemonic: "test test test"}, 1)
+	assert.Error(t, err)
+
+	_, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletDerivationPath: "m/x"}, 1)
+	assert.Error(t, err)
+
+	_, err = MakeWalletKey(Config{WalletMnemonic: testMnemonic, WalletKeyFile: mnemonicFile})
+	assert.ErrorIs(t, err, ErrConflictingWalletKey)
+
+	_, err = MakeWalletKey(Config{WalletMnemonic: testMnemonic, WalletIndex: 1 << 31})
+	assert.ErrorIs(t, err, wallet.ErrInvalidIndex)
+
+	_, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletIndex: 1<<31 - 1}, 2)
+	assert.ErrorIs(t, err, wallet.ErrInvalidIndex)
+
+	_, err = DeriveAddresses(Config{WalletMnemonic: testMnemonic, WalletIndex: math.MaxUint32}, 2)
+	assert.ErrorIs(t, err, wallet.ErrInvalidIndex)
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "funder"
+
+const (
+	tokenLabelNative = "native"
+	tokenLabelSwarm  = "swarm"
+)
+
+// Metrics are funder prometheus metrics, including metrics of the funding
+// wallet. They are not registered, use Collectors to register them in a
+// registry. Amounts are in whole tokens, not in token base units.
+type Metrics struct {
+	FundingWalletBalance *prometheus.GaugeVec
+	NodeBalance          *prometheus.GaugeVec
+	Transfers            *prometheus.CounterVec
+	TransferredAmount    *prometheus.CounterVec
+	StakeTopUps          *prometheus.CounterVec
+	StakedAmount         prometheus.Counter
+	Wallet               *wallet.Metrics
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		FundingWalletBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "funding_wallet_balance",
+			Help:      "Balance of the funding wallet after the last funding run.",
+		}, []string{"token"}),
+		NodeBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "node_balance",
+			Help:      "Balance of node wallets observed before they were funded.",
+		}, []string{"node", "address", "token"}),
+		Transfers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "transfers_total",
+			Help:      "Number of top-up transfers by outcome.",
+		}, []string{"token", "status"}),
+		TransferredAmount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "transferred_amount_total",
+			Help:      "Amount transferred to node wallets.",
+		}, []string{"token"}),
+		StakeTopUps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "stake_top_ups_total",
+			Help:      "Number of node stake top-ups by outcome.",
+		}, []string{"status"}),
+		StakedAmount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "staked_amount_total",
+			Help:      "Amount of swarm tokens staked by nodes.",
+		}),
+		Wallet: wallet.NewMetrics(),
+	}
+}
+
+// Collectors returns all metrics collectors.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return append([]prometheus.Collector{
+		m.FundingWalletBalance,
+		m.NodeBalance,
+		m.Transfers,
+		m.TransferredAmount,
+		m.StakeTopUps,
+		m.StakedAmount,
+	}, m.Wallet.Collectors()...)
+}
+
+func (m *Metrics) walletOptions() []wallet.WalletOptions {
+	if m == nil {
+		return nil
+	}
+
+	return []wallet.WalletOptions{wallet.WithMetricsOption(m.Wallet)}
+}
+
+func (m *Metrics) observeFundReport(report FundReport) {
+	if m == nil {
+		return
+	}
+
+	for _, wr := range report.Wallets {
+		m.observeNodeBalance(wr.Wallet, tokenLabelNative, wr.BalanceBefore.NativeCoin, report.NativeCoin)
+		m.observeNodeBalance(wr.Wallet, tokenLabelSwarm, wr.BalanceBefore.SwarmToken, report.SwarmToken)
+		m.observeTransfer(tokenLabelNative, wr.Transferred.NativeCoin, wr.NativeErr, report.NativeCoin)
+		m.observeTransfer(tokenLabelSwarm, wr.Transferred.SwarmToken, wr.SwarmErr, report.SwarmToken)
+	}
+}
+
+func (m *Metrics) observeNodeBalance(wi WalletInfo, tokenLabel string, balance *big.Int, token wallet.Token) {
+	if balance == nil {
+		return
+	}
+
+	m.NodeBalance.WithLabelValues(wi.Name, wi.Address, tokenLabel).Set(tokenAmount(balance, token))
+}
+
+func (m *Metrics) observeTransfer(tokenLabel string, transferred *big.Int, err error, token wallet.Token) {
+	switch {
+	case err != nil && !errors.Is(err, ErrSpendingCapExceeded):
+		m.Transfers.WithLabelValues(tokenLabel, "failed").Inc()
+	case transferred != nil && transferred.Sign() > 0:
+		m.Transfers.WithLabelValues(tokenLabel, "sent").Inc()
+		m.TransferredAmount.WithLabelValues(tokenLabel).Add(tokenAmount(transferred, token))
+	}
+}
+
+// observeFundingBalance fetches and records balance of the funding wallet.
+func (m *Metrics) observeFundingBalance(
+	ctx context.Context,
+	fundingWallet *wallet.Wallet,
+	nativeCoin, swarmToken wallet.Token,
+	log logging.Logger,
+) {
+	if m == nil {
+		return
+	}
+
+	address := fundingWallet.PublicAddress()
+
+	if balance, err := fundingWallet.Native().Balance(ctx, address, nativeCoin); err != nil {
+		log.Errorf("getting funding wallet's native coin balance failed: %v", err)
+	} else {
+		m.FundingWalletBalance.WithLabelValues(tokenLabelNative).Set(tokenAmount(balance, nativeCoin))
+	}
+
+	if balance, err := fundingWallet.ERC20().Balance(ctx, address, swarmToken); err != nil {
+		log.Errorf("getting funding wallet's swarm token balance failed: %v", err)
+	} else {
+		m.FundingWalletBalance.WithLabelValues(tokenLabelSwarm).Set(tokenAmount(balance, swarmToken))
+	}
+}
+
+func (m *Metrics) observeStakeReport(report StakeReport) {
+	if m == nil {
+		return
+	}
+
+	for _, nr := range report.Nodes {
+		m.StakeTopUps.WithLabelValues(string(nr.Status)).Inc()
+	}
+
+	m.StakedAmount.Add(tokenAmount(report.Totals.Amount, stakeToken))
+}
+
+// tokenAmount converts amount in token base units to whole tokens.
+func tokenAmount(amount *big.Int, token wallet.Token) float64 {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(unit)).Float64()
+
+	return f
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	fundermock "github.com/ethersphere/node-funder/pkg/funder/mock"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+func Test_Metrics(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := generateKey(t)
+	m := NewMetrics()
+
+	registry := prometheus.NewRegistry()
+	for _, c := range m.Collectors() {
+		assert.NoError(t, registry.Register(c))
+	}
+
+	w := wallet.New(newFundedBackendClient(t, key), key, wallet.WithMetricsOption(m.Wallet))
+	cfg := Config{
+		Addresses: []string{
+			"0x95f8916183f7C7154e49396507F5b0FafA4d8077",
+			"0x95f8916183f7C7154e49396507F5b0FafA4d8071",
+		},
+		MinAmounts: MinAmounts{NativeCoin: "3"},
+	}
+
+	_, err := Fund(ctx, cfg, fundermock.NewNodeLister(nil), w, WithMetricsOption(m))
+	assert.NoError(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.Transfers.WithLabelValues("native", "sent")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.Transfers.WithLabelValues("swarm", "sent")))
+	assert.Equal(t, float64(4), testutil.ToFloat64(m.TransferredAmount.WithLabelValues("native")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.NodeBalance.WithLabelValues("wallet (address="+cfg.Addresses[0]+")", cfg.Addresses[0], "native")))
+	assert.Equal(t, float64(1000), testutil.ToFloat64(m.FundingWalletBalance.WithLabelValues("native")))
+	assert.Positive(t, testutil.CollectAndCount(m.Wallet.RPCCallDuration))
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mock
+
+import (
+	"context"
+
+	"github.com/ethersphere/node-funder/pkg/funder"
+)
+
+// NewNodeWatcher returns watcher sending nodes received from nodeC. The watch
+// ends when nodeC is closed.
+func NewNodeWatcher(nodeC <-chan funder.NodeInfo) funder.NodeWatcher {
+	return &nodeWatcher{nodeC: nodeC}
+}
+
+type nodeWatcher struct {
+	nodeC <-chan funder.NodeInfo
+}
+
+func (nw *nodeWatcher) Watch(ctx context.Context, namespace string) (<-chan funder.NodeInfo, error) {
+	out := make(chan funder.NodeInfo)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case node, ok := <-nw.nodeC:
+				if !ok {
+					return
+				}
+
+				select {
+				case out <- node:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -6,10 +6,8 @@ package funder
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"os"
+	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/logging"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,45 +15,66 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/tools/clientcmd/api"
 )
 
 const (
-	beeWalletEndpoint    = "/wallet"
-	beeAddressesEndpoint = "/addresses"
+	// readyPollInterval is how often pods are listed while waiting for them
+	// to become ready.
+	readyPollInterval = 5 * time.Second
+	// minReadyPollInterval bounds the poll interval of short ready timeouts.
+	minReadyPollInterval = 100 * time.Millisecond
 )
 
 type NodeLister interface {
 	List(ctx context.Context, namespace string) ([]NodeInfo, error)
 }
 
-func newNodeLister() (NodeLister, error) {
-	client, err := newKube()
+func newNodeLister(cfg Config) (NodeLister, error) {
+	if cfg.Inventory != "" {
+		return newInventoryLister(cfg.Inventory)
+	}
+
+	if cfg.Docker {
+		return newDockerLister(cfg)
+	}
+
+	api, err := newBeeAPI(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newKube(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	return &nodeLister{
-		client: client,
+		client:        client,
+		labelSelector: cfg.LabelSelector,
+		fieldSelector: cfg.FieldSelector,
+		api:           api,
 	}, nil
 }
 
 type nodeLister struct {
-	client *corev1client.CoreV1Client
+	client        *corev1client.CoreV1Client
+	labelSelector string
+	fieldSelector string
+	api           beeAPI
 }
 
 func (nl *nodeLister) List(ctx context.Context, namespace string) ([]NodeInfo, error) {
-	pods, err := nl.client.Pods(namespace).List(ctx, metav1.ListOptions{})
+	pods, err := nl.client.Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: nl.labelSelector,
+		FieldSelector: nl.fieldSelector,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed listing pods: %w", err)
 	}
 
 	result := make([]NodeInfo, 0, len(pods.Items))
-	for _, pod := range pods.Items {
-		result = append(result, NodeInfo{
-			Name:    pod.Name,
-			Address: fmt.Sprintf("http://%s:1633", pod.Status.PodIP),
-		})
+	for i := range pods.Items {
+		result = append(result, nodeInfoFromPod(&pods.Items[i], nl.api))
 	}
 
 	return result, nil
@@ -66,8 +85,8 @@ type walletInfoResponse struct {
 	Error      error
 }
 
-func newKube() (*corev1client.CoreV1Client, error) {
-	config, err := makeConfig()
+func newKube(cfg Config) (*corev1client.CoreV1Client, error) {
+	config, err := makeConfig(cfg.Kubeconfig, cfg.KubeContext)
 	if err != nil {
 		return nil, fmt.Errorf("get configuration failed: %w", err)
 	}
@@ -80,102 +99,210 @@ func newKube() (*corev1client.CoreV1Client, error) {
 	return coreClient, nil
 }
 
-func makeConfig() (*rest.Config, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("obtaining user's home dir failed: %w", err)
+// makeConfig loads the kubeconfig file, or files listed in KUBECONFIG merged
+// by kubectl rules, or $HOME/.kube/config. When there is no kubeconfig and
+// neither the file nor the context are set explicitly, the in-cluster service
+// account configuration is used, so the funder can run as a Job.
+func makeConfig(kubeconfig, kubeContext string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext}).ClientConfig()
+	if err == nil {
+		return config, nil
+	}
+
+	if kubeconfig != "" || kubeContext != "" || !clientcmd.IsEmptyConfig(err) {
+		return nil, err
+	}
+
+	config, icErr := rest.InClusterConfig()
+	if icErr != nil {
+		return nil, fmt.Errorf("no kubeconfig found and not running in cluster: %w", icErr)
+	}
+
+	return config, nil
+}
+
+// listBeeNodes lists nodes of all configured namespaces. Unless bee pods are
+// selected by a configured selector, nodes which do not look like bee nodes by
+// their name are omitted. Nodes which are not ready are returned separately,
+// after waiting up to cfg.ReadyTimeout for them to become ready.
+func listBeeNodes(ctx context.Context, cfg Config, nl NodeLister, log logging.Logger) (ready, skipped []NodeInfo, err error) {
+	if err := cfg.validateNamespaces(); err != nil {
+		return nil, nil, err
 	}
 
-	kubeconfigPath := home + "/.kube/config"
+	deadline := time.Now().Add(cfg.ReadyTimeout)
+
+	var omitted []NodeInfo
+
+	for {
+		var nodes []NodeInfo
+
+		nodes, omitted, err = listNamespacesNodes(ctx, cfg, nl)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ready, skipped = splitReadyNodes(nodes)
+		if len(skipped) == 0 || !time.Now().Before(deadline) {
+			break
+		}
+
+		log.Infof("waiting for pods (count=%d) to become ready", len(skipped))
 
-	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
-		&clientcmd.ConfigOverrides{ClusterInfo: api.Cluster{Server: ""}}).ClientConfig()
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(max(min(readyPollInterval, cfg.ReadyTimeout/10), minReadyPollInterval)):
+		}
+	}
+
+	if len(omitted) > 0 {
+		log.Infof("ignoring pods %v", omitted)
+	}
+
+	for _, n := range skipped {
+		log.Warningf("skipping pod %s - %s", n.key(), n.SkipReason)
+	}
+
+	return ready, skipped, nil
 }
 
-func fetchNamespaceNodeInfo(ctx context.Context, namespace string, chainID int64, nl NodeLister, log logging.Logger) (NamespaceNodes, error) {
-	nodes, err := nl.List(ctx, namespace)
+func listNamespacesNodes(ctx context.Context, cfg Config, nl NodeLister) (nodes, omitted []NodeInfo, err error) {
+	for _, namespace := range cfg.namespaces() {
+		namespaceNodes, err := nl.List(ctx, namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing nodes in namespace %q failed: %w", namespace, err)
+		}
+
+		for _, n := range namespaceNodes {
+			if n.Namespace == "" {
+				n.Namespace = namespace
+			}
+
+			nodes = append(nodes, n)
+		}
+	}
+
+	if !cfg.filterByName() {
+		return nodes, nil, nil
+	}
+
+	nodes, omitted = filterBeeNodes(nodes)
+
+	return nodes, omitted, nil
+}
+
+func splitReadyNodes(nodes []NodeInfo) (ready, notReady []NodeInfo) {
+	for _, n := range nodes {
+		if n.SkipReason == "" {
+			ready = append(ready, n)
+		} else {
+			notReady = append(notReady, n)
+		}
+	}
+
+	return ready, notReady
+}
+
+func fetchNamespaceNodeInfo(ctx context.Context, cfg Config, chainID int64, nl NodeLister, pool *workerPool, log logging.Logger) (NamespaceNodes, error) {
+	nodes, skipped, err := listBeeNodes(ctx, cfg, nl, log)
 	if err != nil {
-		return NamespaceNodes{}, fmt.Errorf("listing nodes failed: %w", err)
+		return NamespaceNodes{}, err
+	}
+
+	client, err := newAPIClient(cfg)
+	if err != nil {
+		return NamespaceNodes{}, err
 	}
 
 	walletInfoResponseC := make(chan walletInfoResponse, len(nodes))
 
 	for _, nodeInfo := range nodes {
-		go func(nodeInfo NodeInfo) {
+		name := nodeInfo.Name
+		if len(cfg.namespaces()) > 1 || cfg.AllNamespaces {
+			name = nodeInfo.Namespace + "/" + nodeInfo.Name
+		}
+
+		pool.submit(func() {
+			var res walletInfoResponse
+
 			if chainID == 0 {
-				wi, err := fetchWalletInfo(ctx, nodeInfo.Address)
-				walletInfoResponseC <- walletInfoResponse{
-					WalletInfo: NewWalletInfo(nodeInfo.Name, wi.Address, wi.ChainID),
+				wi, err := fetchWalletInfo(ctx, client, nodeInfo)
+				res = walletInfoResponse{
+					WalletInfo: NewWalletInfo(name, wi.Address, wi.ChainID),
 					Error:      err,
 				}
 			} else {
-				address, err := fetchAddressInfo(ctx, nodeInfo.Address)
-				walletInfoResponseC <- walletInfoResponse{
-					WalletInfo: NewWalletInfo(nodeInfo.Name, address, chainID),
+				address, err := fetchAddressInfo(ctx, client, nodeInfo)
+				res = walletInfoResponse{
+					WalletInfo: NewWalletInfo(name, address, chainID),
 					Error:      err,
 				}
 			}
-		}(nodeInfo)
+
+			res.WalletInfo.Namespace = nodeInfo.Namespace
+			walletInfoResponseC <- res
+		})
 	}
 
 	nodeWallets := make([]WalletInfo, 0)
+	failedWallets := make([]WalletReport, 0)
 
 	for i := 0; i < len(nodes); i++ {
 		res := <-walletInfoResponseC
 		if res.Error == nil {
 			nodeWallets = append(nodeWallets, res.WalletInfo)
-		} else {
-			log.Errorf("fetching wallet info for node %s failed: %v", res.WalletInfo.Name, res.Error)
+			continue
 		}
+
+		log.Errorf("fetching wallet info for node %s failed: %v", res.WalletInfo.Name, res.Error)
+
+		failedWallets = append(failedWallets, WalletReport{
+			Wallet:      res.WalletInfo,
+			Status:      WalletStatusFailed,
+			Transferred: newAmounts(),
+			Err:         fmt.Errorf("%w: %w", ErrFailedFetchingWallet, res.Error),
+		})
 	}
 
 	return NamespaceNodes{
-		Name:        namespace,
-		NodeWallets: nodeWallets,
+		Name:          cfg.namespacesString(),
+		NodeWallets:   nodeWallets,
+		FailedWallets: failedWallets,
+		SkippedNodes:  skipped,
 	}, nil
 }
 
-func fetchWalletInfo(ctx context.Context, nodeAddress string) (WalletInfo, error) {
-	response, err := sendHTTPRequest(ctx, http.MethodGet, nodeAddress+beeWalletEndpoint)
+func fetchWalletInfo(ctx context.Context, client *apiClient, node NodeInfo) (WalletInfo, error) {
+	wallet, err := client.node(node).Wallet(ctx)
 	if err != nil {
 		return WalletInfo{}, fmt.Errorf("get bee wallet info failed: %w", err)
 	}
 
-	walletResponse := struct {
-		WalletAddress string `json:"walletAddress"`
-		ChainID       int64  `json:"chainID"`
-	}{}
-	if err := json.Unmarshal(response, &walletResponse); err != nil {
-		return WalletInfo{}, fmt.Errorf("failed to unmarshal wallet response :%w", err)
-	}
-
-	if walletResponse.WalletAddress == "" {
+	if wallet.WalletAddress == "" {
 		return WalletInfo{}, fmt.Errorf("failed getting bee node wallet address")
 	}
 
 	return WalletInfo{
-		Address: walletResponse.WalletAddress,
-		ChainID: walletResponse.ChainID,
+		Address: wallet.WalletAddress,
+		ChainID: wallet.ChainID,
 	}, nil
 }
 
-func fetchAddressInfo(ctx context.Context, nodeAddress string) (string, error) {
-	response, err := sendHTTPRequest(ctx, http.MethodGet, nodeAddress+beeAddressesEndpoint)
+func fetchAddressInfo(ctx context.Context, client *apiClient, node NodeInfo) (string, error) {
+	addresses, err := client.node(node).Addresses(ctx)
 	if err != nil {
 		return "", fmt.Errorf("get bee wallet info failed: %w", err)
 	}
 
-	walletResponse := struct {
-		WalletAddress string `json:"ethereum"`
-	}{}
-	if err := json.Unmarshal(response, &walletResponse); err != nil {
-		return "", fmt.Errorf("failed to unmarshal wallet response :%w", err)
-	}
-
-	if walletResponse.WalletAddress == "" {
+	if addresses.Ethereum == "" {
 		return "", fmt.Errorf("failed getting bee node wallet address")
 	}
 
-	return walletResponse.WalletAddress, nil
+	return addresses.Ethereum, nil
 }
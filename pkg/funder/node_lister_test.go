@@ -0,0 +1,79 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+)
+
+const testKubeconfig = `
+apiVersion: v1
+kind: Config
+clusters:
+  - name: dev
+    cluster:
+      server: https://dev.example:6443
+  - name: prod
+    cluster:
+      server: https://prod.example:6443
+users:
+  - name: funder
+    user:
+      token: token
+contexts:
+  - name: dev
+    context:
+      cluster: dev
+      user: funder
+  - name: prod
+    context:
+      cluster: prod
+      user: funder
+current-context: dev
+`
+
+func Test_MakeConfig(t *testing.T) {
+	t.Parallel()
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(testKubeconfig), 0o600))
+
+	t.Run("current context", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := MakeConfig(kubeconfig, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://dev.example:6443", config.Host)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := MakeConfig(kubeconfig, "prod")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://prod.example:6443", config.Host)
+	})
+
+	t.Run("unknown context", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := MakeConfig(kubeconfig, "staging")
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		// explicitly set kubeconfig does not fall back to in-cluster config
+		_, err := MakeConfig(filepath.Join(t.TempDir(), "missing"), "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NodeWatcher watches nodes becoming available.
+type NodeWatcher interface {
+	// Watch sends node every time its pod becomes ready, including pods which
+	// are ready when the watch starts. The channel is closed once ctx is done.
+	Watch(ctx context.Context, namespace string) (<-chan NodeInfo, error)
+}
+
+func newNodeWatcher(cfg Config) (NodeWatcher, error) {
+	api, err := newBeeAPI(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newKube(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &podWatcher{
+		client:        client,
+		labelSelector: cfg.LabelSelector,
+		fieldSelector: cfg.FieldSelector,
+		api:           api,
+	}, nil
+}
+
+// podWatcher is NodeWatcher backed by a pod informer.
+type podWatcher struct {
+	client        corev1client.PodsGetter
+	labelSelector string
+	fieldSelector string
+	api           beeAPI
+}
+
+func (w *podWatcher) Watch(ctx context.Context, namespace string) (<-chan NodeInfo, error) {
+	pods := w.client.Pods(namespace)
+
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = w.labelSelector
+			options.FieldSelector = w.fieldSelector
+
+			return pods.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = w.labelSelector
+			options.FieldSelector = w.fieldSelector
+
+			return pods.Watch(ctx, options)
+		},
+	}, &corev1.Pod{}, 0, cache.Indexers{})
+
+	nodeC := make(chan NodeInfo)
+
+	send := func(node NodeInfo) {
+		select {
+		case nodeC <- node:
+		case <-ctx.Done():
+		}
+	}
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok && isPodReady(pod) {
+				send(nodeInfoFromPod(pod, w.api))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod, ok := oldObj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+
+			pod, ok := newObj.(*corev1.Pod)
+			if !ok || !isPodReady(pod) {
+				return
+			}
+
+			// only transitions to ready, periodic updates of ready pods are ignored
+			if !isPodReady(oldPod) || oldPod.Status.PodIP != pod.Status.PodIP {
+				send(nodeInfoFromPod(pod, w.api))
+			}
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding pod event handler failed: %w", err)
+	}
+
+	go func() {
+		defer close(nodeC)
+		informer.Run(ctx.Done())
+	}()
+
+	return nodeC, nil
+}
+
+// isPodReady reports whether the pod is running, has an IP assigned and
+// passes its readiness checks.
+func isPodReady(pod *corev1.Pod) bool {
+	return podNotReadyReason(pod) == ""
+}
+
+// podNotReadyReason returns why the pod is not ready, or empty string when it
+// is ready.
+func podNotReadyReason(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "pod is terminating"
+	}
+
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Sprintf("pod phase is %s", pod.Status.Phase)
+	}
+
+	if pod.Status.PodIP == "" {
+		return "pod IP is not assigned"
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			continue
+		}
+
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return fmt.Sprintf("container %s is not ready (%s)", cs.Name, cs.State.Waiting.Reason)
+		}
+
+		return fmt.Sprintf("container %s is not ready", cs.Name)
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status != corev1.ConditionTrue {
+			return "pod is not ready"
+		}
+	}
+
+	return ""
+}
+
+func nodeInfoFromPod(pod *corev1.Pod, api beeAPI) NodeInfo {
+	node := NodeInfo{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		SkipReason: podNotReadyReason(pod),
+	}
+
+	address, err := api.address(pod)
+	if err != nil && node.SkipReason == "" {
+		node.SkipReason = err.Error()
+	}
+
+	node.Address = address
+
+	return node
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+var ErrTransferPending = errors.New("previous transfer not mined yet")
+
+// pendingTransfers tracks transfers which were sent but not confirmed when
+// their funding ended, so later fundings do not top up their wallets again
+// while the transfers may still be mined.
+type pendingTransfers struct {
+	mu     sync.Mutex
+	hashes map[common.Address][]common.Hash
+}
+
+func newPendingTransfers() *pendingTransfers {
+	return &pendingTransfers{
+		hashes: make(map[common.Address][]common.Hash),
+	}
+}
+
+// add records transfers of the reports which were sent, but neither mined nor
+// reverted.
+func (p *pendingTransfers) add(reports []WalletReport) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, wr := range reports {
+		address := common.HexToAddress(wr.Wallet.Address)
+
+		for _, t := range []struct {
+			hash common.Hash
+			err  error
+		}{
+			{hash: wr.NativeTxHash, err: wr.NativeErr},
+			{hash: wr.SwarmTxHash, err: wr.SwarmErr},
+		} {
+			if t.hash == (common.Hash{}) || t.err == nil || errors.Is(t.err, wallet.ErrTransactionReverted) {
+				continue
+			}
+
+			p.hashes[address] = append(p.hashes[address], t.hash)
+		}
+	}
+}
+
+// filter returns wallets without pending transfers, and reports of wallets
+// skipped because their transfers are still pending. Transfers mined or
+// dropped meanwhile are forgotten.
+func (p *pendingTransfers) filter(
+	ctx context.Context,
+	fundingWallet *wallet.Wallet,
+	wallets []WalletInfo,
+) ([]WalletInfo, []WalletReport) {
+	if p == nil {
+		return wallets, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var (
+		ready   = make([]WalletInfo, 0, len(wallets))
+		skipped []WalletReport
+	)
+
+	for _, wi := range wallets {
+		address := common.HexToAddress(wi.Address)
+
+		if err := p.refresh(ctx, fundingWallet, address); err != nil {
+			skipped = append(skipped, WalletReport{
+				Wallet:      wi,
+				Status:      WalletStatusSkipped,
+				Transferred: newAmounts(),
+				Err:         err,
+			})
+
+			continue
+		}
+
+		ready = append(ready, wi)
+	}
+
+	return ready, skipped
+}
+
+// refresh forgets transfers to the address which are no longer pending. It
+// returns error wrapping ErrTransferPending while some transfer is pending, or
+// its status could not be fetched.
+func (p *pendingTransfers) refresh(ctx context.Context, fundingWallet *wallet.Wallet, address common.Address) error {
+	hashes := p.hashes[address]
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	remaining := hashes[:0]
+
+	var err error
+
+	for _, hash := range hashes {
+		pending, e := fundingWallet.TransactionPending(ctx, hash)
+		switch {
+		case e != nil:
+			err = fmt.Errorf("%w (tx %s): %w", ErrTransferPending, hash, e)
+		case pending:
+			err = fmt.Errorf("%w (tx %s)", ErrTransferPending, hash)
+		default:
+			continue
+		}
+
+		remaining = append(remaining, hash)
+	}
+
+	if len(remaining) == 0 {
+		delete(p.hashes, address)
+	} else {
+		p.hashes[address] = remaining
+	}
+
+	return err
+}
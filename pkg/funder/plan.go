@@ -0,0 +1,406 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+// Amounts holds an amount of native coin and swarm token in base units.
+type Amounts struct {
+	NativeCoin *big.Int `json:"nativeCoin"`
+	SwarmToken *big.Int `json:"swarmToken"`
+}
+
+func newAmounts() Amounts {
+	return Amounts{
+		NativeCoin: big.NewInt(0),
+		SwarmToken: big.NewInt(0),
+	}
+}
+
+// MarshalJSON encodes amounts as decimal strings, as amounts in base units
+// exceed precision of JSON numbers in most decoders.
+func (a Amounts) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		NativeCoin *string `json:"nativeCoin"`
+		SwarmToken *string `json:"swarmToken"`
+	}{
+		NativeCoin: decimalString(a.NativeCoin),
+		SwarmToken: decimalString(a.SwarmToken),
+	})
+}
+
+// decimalString returns decimal representation of the amount, nil when the
+// amount is nil.
+func decimalString(amount *big.Int) *string {
+	if amount == nil {
+		return nil
+	}
+
+	s := amount.String()
+
+	return &s
+}
+
+// FundingPlan describes the transfers a funding run would make without
+// making them.
+type FundingPlan struct {
+	FundingAddress common.Address
+	NativeCoin     wallet.Token
+	SwarmToken     wallet.Token
+	FundingBalance Amounts
+	Wallets        []WalletPlan
+	TotalTopUp     Amounts
+	TotalFee       *big.Int // estimated fee of all transfers, in native coin
+	// SkippedNodes are nodes which are not planned because they are not ready.
+	SkippedNodes []SkippedNode
+}
+
+// WalletPlan describes the transfers planned for a single wallet.
+type WalletPlan struct {
+	Wallet  WalletInfo
+	Balance Amounts
+	TopUp   Amounts
+	Fee     *big.Int
+	Err     error
+}
+
+// Required returns amounts the funding wallet needs to execute the plan.
+func (p FundingPlan) Required() Amounts {
+	return Amounts{
+		NativeCoin: new(big.Int).Add(p.TotalTopUp.NativeCoin, p.TotalFee),
+		SwarmToken: new(big.Int).Set(p.TotalTopUp.SwarmToken),
+	}
+}
+
+// Shortfall returns amounts missing on the funding wallet to execute the plan.
+func (p FundingPlan) Shortfall() Amounts {
+	required := p.Required()
+
+	return Amounts{
+		NativeCoin: shortfall(required.NativeCoin, p.FundingBalance.NativeCoin),
+		SwarmToken: shortfall(required.SwarmToken, p.FundingBalance.SwarmToken),
+	}
+}
+
+// Covered reports whether the funding wallet balance covers the whole plan
+// and every wallet could be planned.
+func (p FundingPlan) Covered() bool {
+	for _, wp := range p.Wallets {
+		if wp.Err != nil {
+			return false
+		}
+	}
+
+	s := p.Shortfall()
+
+	return s.NativeCoin.Sign() == 0 && s.SwarmToken.Sign() == 0
+}
+
+// Prioritize splits planned wallets into those the funding wallet balance can
+// cover and those it cannot. Wallets with the lowest native coin balance are
+// covered first. Wallets which could not be planned are always included so
+// their funding failure gets reported.
+func (p FundingPlan) Prioritize() (covered, skipped []WalletInfo) {
+	wallets := make([]WalletPlan, len(p.Wallets))
+	copy(wallets, p.Wallets)
+
+	sort.SliceStable(wallets, func(i, j int) bool {
+		return wallets[i].Balance.NativeCoin.Cmp(wallets[j].Balance.NativeCoin) < 0
+	})
+
+	remainingNative := new(big.Int).Set(p.FundingBalance.NativeCoin)
+	remainingSwarm := new(big.Int).Set(p.FundingBalance.SwarmToken)
+
+	for _, wp := range wallets {
+		if wp.Err != nil {
+			covered = append(covered, wp.Wallet)
+			continue
+		}
+
+		native := new(big.Int).Add(wp.TopUp.NativeCoin, wp.Fee)
+		if native.Cmp(remainingNative) > 0 || wp.TopUp.SwarmToken.Cmp(remainingSwarm) > 0 {
+			skipped = append(skipped, wp.Wallet)
+			continue
+		}
+
+		remainingNative.Sub(remainingNative, native)
+		remainingSwarm.Sub(remainingSwarm, wp.TopUp.SwarmToken)
+
+		covered = append(covered, wp.Wallet)
+	}
+
+	return covered, skipped
+}
+
+// WriteTable writes the plan in a human readable table.
+func (p FundingPlan) WriteTable(w io.Writer) error {
+	native := func(a *big.Int) string { return formatAmount(a, p.NativeCoin.Decimals) }
+	swarm := func(a *big.Int) string { return formatAmount(a, p.SwarmToken.Decimals) }
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "WALLET\tADDRESS\t%[1]s BALANCE\t%[2]s BALANCE\t%[1]s TOP-UP\t%[2]s TOP-UP\tEST. FEE\tERROR\n", p.NativeCoin.Symbol, p.SwarmToken.Symbol)
+
+	for _, wp := range p.Wallets {
+		errMsg := "-"
+		if wp.Err != nil {
+			errMsg = wp.Err.Error()
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			wp.Wallet.Name, wp.Wallet.Address,
+			native(wp.Balance.NativeCoin), swarm(wp.Balance.SwarmToken),
+			native(wp.TopUp.NativeCoin), swarm(wp.TopUp.SwarmToken),
+			native(wp.Fee), errMsg,
+		)
+	}
+
+	for _, n := range p.SkippedNodes {
+		name := n.Name
+		if n.Namespace != "" {
+			name = n.Namespace + "/" + n.Name
+		}
+
+		fmt.Fprintf(tw, "node (%s)\t-\t-\t-\t-\t-\t-\tskipped - %s\n", name, n.Reason)
+	}
+
+	required := p.Required()
+	missing := p.Shortfall()
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "\t\t%s\t%s\n", p.NativeCoin.Symbol, p.SwarmToken.Symbol)
+	fmt.Fprintf(tw, "funding wallet (%s) balance\t\t%s\t%s\n", p.FundingAddress, native(p.FundingBalance.NativeCoin), swarm(p.FundingBalance.SwarmToken))
+	fmt.Fprintf(tw, "total top-up\t\t%s\t%s\n", native(p.TotalTopUp.NativeCoin), swarm(p.TotalTopUp.SwarmToken))
+	fmt.Fprintf(tw, "total estimated fee\t\t%s\t\n", native(p.TotalFee))
+	fmt.Fprintf(tw, "total required\t\t%s\t%s\n", native(required.NativeCoin), swarm(required.SwarmToken))
+	fmt.Fprintf(tw, "shortfall\t\t%s\t%s\n", native(missing.NativeCoin), swarm(missing.SwarmToken))
+
+	return tw.Flush()
+}
+
+// Plan computes the transfers Fund would make with the same arguments,
+// without transferring anything.
+func Plan(
+	ctx context.Context,
+	cfg Config,
+	nl NodeLister,
+	fundingWallet *wallet.Wallet,
+	options ...FunderOptions,
+) (FundingPlan, error) {
+	var err error
+
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if fundingWallet == nil {
+		fundingWallet, err = makeFundingWallet(ctx, cfg, opts.metrics.walletOptions()...)
+		if err != nil {
+			return FundingPlan{}, fmt.Errorf("make funding wallet: %w", err)
+		}
+
+		defer fundingWallet.Close()
+	}
+
+	nativeCoin, swarmToken, err := fundingTokens(ctx, fundingWallet)
+	if err != nil {
+		return FundingPlan{}, err
+	}
+
+	policy, err := cfg.fundingPolicy(nativeCoin, swarmToken)
+	if err != nil {
+		return FundingPlan{}, err
+	}
+
+	pool := newWorkerPool(opts.concurrency)
+
+	wallets, _, skippedNodes, err := listWallets(ctx, cfg, nl, fundingWallet, pool, opts.log)
+	if err != nil {
+		return FundingPlan{}, err
+	}
+
+	plan, err := makePlan(ctx, fundingWallet, policy, wallets, pool)
+	if err != nil {
+		return FundingPlan{}, err
+	}
+
+	plan.SkippedNodes = newSkippedNodes(skippedNodes)
+
+	return plan, nil
+}
+
+func makePlan(
+	ctx context.Context,
+	fundingWallet *wallet.Wallet,
+	policy fundingPolicy,
+	wallets []WalletInfo,
+	pool *workerPool,
+) (FundingPlan, error) {
+	nativeCoin, swarmToken, err := fundingTokens(ctx, fundingWallet)
+	if err != nil {
+		return FundingPlan{}, err
+	}
+
+	plan := FundingPlan{
+		FundingAddress: fundingWallet.PublicAddress(),
+		NativeCoin:     nativeCoin,
+		SwarmToken:     swarmToken,
+		FundingBalance: newAmounts(),
+		Wallets:        make([]WalletPlan, len(wallets)),
+		TotalTopUp:     newAmounts(),
+		TotalFee:       big.NewInt(0),
+	}
+
+	plan.FundingBalance.NativeCoin, err = fundingWallet.Native().Balance(ctx, plan.FundingAddress, nativeCoin)
+	if err != nil {
+		return FundingPlan{}, fmt.Errorf("getting funding wallet's native coin balance failed: %w", err)
+	}
+
+	plan.FundingBalance.SwarmToken, err = fundingWallet.ERC20().Balance(ctx, plan.FundingAddress, swarmToken)
+	if err != nil {
+		return FundingPlan{}, fmt.Errorf("getting funding wallet's swarm token balance failed: %w", err)
+	}
+
+	planC := make([]<-chan WalletPlan, len(wallets))
+	for i, wi := range wallets {
+		planC[i] = planWalletAsync(ctx, fundingWallet, policy, wi, pool)
+	}
+
+	for i, c := range planC {
+		wp := <-c
+		plan.Wallets[i] = wp
+
+		// top-ups of wallets which could not be fully planned are counted
+		// too, e.g. fee estimation of a transfer fails when the funding
+		// wallet cannot cover it
+		plan.TotalTopUp.NativeCoin.Add(plan.TotalTopUp.NativeCoin, wp.TopUp.NativeCoin)
+		plan.TotalTopUp.SwarmToken.Add(plan.TotalTopUp.SwarmToken, wp.TopUp.SwarmToken)
+		plan.TotalFee.Add(plan.TotalFee, wp.Fee)
+	}
+
+	return plan, nil
+}
+
+func planWalletAsync(
+	ctx context.Context,
+	fundingWallet *wallet.Wallet,
+	policy fundingPolicy,
+	wi WalletInfo,
+	pool *workerPool,
+) <-chan WalletPlan {
+	respC := make(chan WalletPlan, 1)
+
+	pool.submit(func() {
+		respC <- planWallet(ctx, fundingWallet, policy, wi)
+	})
+
+	return respC
+}
+
+func planWallet(
+	ctx context.Context,
+	fundingWallet *wallet.Wallet,
+	policy fundingPolicy,
+	wi WalletInfo,
+) WalletPlan {
+	wp := WalletPlan{
+		Wallet:  wi,
+		Balance: newAmounts(),
+		TopUp:   newAmounts(),
+		Fee:     big.NewInt(0),
+	}
+
+	if err := validateChainID(ctx, fundingWallet, wi); err != nil {
+		wp.Err = err
+		return wp
+	}
+
+	nativeBalance, nativeTopUp, nativeFee, nativeErr := planTopUp(ctx, wallet.NativeCoinForChain, fundingWallet.Native(), policy.native, wi)
+	swarmBalance, swarmTopUp, swarmFee, swarmErr := planTopUp(ctx, wallet.SwarmTokenForChain, fundingWallet.ERC20(), policy.swarm, wi)
+
+	if nativeBalance != nil {
+		wp.Balance.NativeCoin = nativeBalance
+		wp.TopUp.NativeCoin = nativeTopUp
+	}
+
+	if swarmBalance != nil {
+		wp.Balance.SwarmToken = swarmBalance
+		wp.TopUp.SwarmToken = swarmTopUp
+	}
+
+	wp.Err = mergeErrors(
+		ErrFailedFunding,
+		mergeErrors(ErrFailedFundingWithNativeToken, nativeErr),
+		mergeErrors(ErrFailedFundingWithSwarmToken, swarmErr),
+	)
+	if wp.Err != nil {
+		return wp
+	}
+
+	wp.Fee.Add(nativeFee, swarmFee)
+
+	return wp
+}
+
+// planTopUp returns the current balance of the wallet, the amount it would be
+// topped up with and the estimated fee of the top up transfer. The balance and
+// the top-up amount are returned also when only the fee estimation fails.
+func planTopUp(
+	ctx context.Context,
+	tokenInfoGetter wallet.TokenInfoGetterFn,
+	fundingWallet wallet.TokenWallet,
+	policy topUpPolicy,
+	wi WalletInfo,
+) (balance, topUp, fee *big.Int, err error) {
+	token, err := tokenInfoGetter(wi.ChainID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !common.IsHexAddress(wi.Address) {
+		return nil, nil, nil, fmt.Errorf("unexpected wallet address")
+	}
+
+	address := common.HexToAddress(wi.Address)
+
+	balance, err = fundingWallet.Balance(ctx, address, token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	topUp = calcTopUpAmount(policy, balance)
+	if topUp.Sign() <= 0 {
+		// Top up is not needed, current balance is sufficient
+		return balance, big.NewInt(0), big.NewInt(0), nil
+	}
+
+	fee, err = fundingWallet.TransferFee(ctx, address, topUp, token)
+	if err != nil {
+		return balance, topUp, nil, err
+	}
+
+	return balance, topUp, fee, nil
+}
+
+func shortfall(required, available *big.Int) *big.Int {
+	missing := new(big.Int).Sub(required, available)
+	if missing.Sign() < 0 {
+		return big.NewInt(0)
+	}
+
+	return missing
+}
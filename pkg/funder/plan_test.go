@@ -0,0 +1,148 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	fundermock "github.com/ethersphere/node-funder/pkg/funder/mock"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	walletmock "github.com/ethersphere/node-funder/pkg/wallet/mock"
+)
+
+func Test_Plan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	bc := walletmock.NewBackendClient()
+	key := generateKey(t)
+	w := wallet.New(bc, key)
+	nl := fundermock.NewNodeLister(nil)
+	addresses := []string{
+		"0x95f8916183f7C7154e49396507F5b0FafA4d8077",
+		"0x95f8916183f7C7154e49396507F5b0FafA4d8071",
+	}
+
+	t.Run("already funded", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Addresses: addresses}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.Len(t, plan.Wallets, 2)
+		assert.Equal(t, "0", plan.TotalTopUp.NativeCoin.String())
+		assert.Equal(t, "0", plan.TotalTopUp.SwarmToken.String())
+		assert.Equal(t, "0", plan.TotalFee.String())
+		assert.True(t, plan.Covered())
+	})
+
+	t.Run("covered", func(t *testing.T) {
+		t.Parallel()
+
+		// wallets hold 1 xDAI and 2.05747762176 xBZZ, so only native coin is topped up
+		cfg := Config{Addresses: addresses[:1], MinAmounts: MinAmounts{NativeCoin: "1.5", SwarmToken: "1"}}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.Equal(t, "500000000000000000", plan.TotalTopUp.NativeCoin.String())
+		assert.Equal(t, "0", plan.TotalTopUp.SwarmToken.String())
+		assert.Positive(t, plan.TotalFee.Sign())
+		assert.True(t, plan.Covered())
+	})
+
+	t.Run("not covered", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Addresses: addresses, MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"}}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.Equal(t, "4000000000000000000", plan.TotalTopUp.NativeCoin.String())
+		assert.Equal(t, "18850447564800000", plan.TotalTopUp.SwarmToken.String())
+		assert.False(t, plan.Covered())
+
+		// funding wallet holds the same balances as the funded wallets
+		shortfall := plan.Shortfall()
+		assert.Equal(t, 1, shortfall.NativeCoin.Cmp(toBigInt("3000000000000000000")))
+		assert.Equal(t, "0", shortfall.SwarmToken.String())
+
+		var buf bytes.Buffer
+		assert.NoError(t, plan.WriteTable(&buf))
+		assert.Contains(t, buf.String(), addresses[0])
+		assert.Contains(t, buf.String(), "shortfall")
+	})
+
+	t.Run("prioritize", func(t *testing.T) {
+		t.Parallel()
+
+		// funding wallet holds 5 xDAI, enough for two wallets only
+		addr, err := key.PublicAddress()
+		assert.NoError(t, err)
+		bc := walletmock.NewBackendClient(walletmock.WithBalance(addr, toBigInt("5000000000000000000"), toBigInt("1000000000000000000")))
+		w := wallet.New(bc, key)
+
+		cfg := Config{
+			Addresses:  append(addresses, "0x95f8916183f7C7154e49396507F5b0FafA4d8072"),
+			MinAmounts: MinAmounts{NativeCoin: "3", SwarmToken: "3"},
+		}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.False(t, plan.Covered())
+
+		covered, skipped := plan.Prioritize()
+		assert.Len(t, covered, 2)
+		assert.Len(t, skipped, 1)
+	})
+
+	t.Run("swarm token not covered", func(t *testing.T) {
+		t.Parallel()
+
+		// funding wallet holds 1000 xDAI and 0.5 xBZZ, estimation of the swarm
+		// token transfer reverts
+		addr, err := key.PublicAddress()
+		assert.NoError(t, err)
+		bc := walletmock.NewBackendClient(walletmock.WithBalance(addr, toBigInt("1000000000000000000000"), toBigInt("5000000000000000")))
+		w := wallet.New(bc, key)
+
+		cfg := Config{Addresses: addresses[:1], MinAmounts: MinAmounts{SwarmToken: "3"}}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, plan.Wallets[0].Err, ErrFailedFundingWithSwarmToken)
+		assert.Equal(t, "9425223782400000", plan.TotalTopUp.SwarmToken.String())
+		assert.Equal(t, "4425223782400000", plan.Shortfall().SwarmToken.String())
+		assert.False(t, plan.Covered())
+	})
+
+	t.Run("not ready node skipped", func(t *testing.T) {
+		t.Parallel()
+
+		nl := fundermock.NewNodeLister([]NodeInfo{
+			{Name: "bee-1", Namespace: "swarm", SkipReason: "pod is Pending"},
+		})
+		cfg := Config{Namespace: "swarm"}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.Empty(t, plan.Wallets)
+		assert.Equal(t, []SkippedNode{{Name: "bee-1", Namespace: "swarm", Reason: "pod is Pending"}}, plan.SkippedNodes)
+
+		var buf bytes.Buffer
+		assert.NoError(t, plan.WriteTable(&buf))
+		assert.Contains(t, buf.String(), "node (swarm/bee-1)")
+		assert.Contains(t, buf.String(), "skipped - pod is Pending")
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{Addresses: []string{"not-an-address"}}
+		plan, err := Plan(ctx, cfg, nl, w)
+		assert.NoError(t, err)
+		assert.Error(t, plan.Wallets[0].Err)
+		assert.False(t, plan.Covered())
+	})
+}
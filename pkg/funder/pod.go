@@ -0,0 +1,106 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultAPIPort is port of bee API when it is not discovered from the pod.
+	defaultAPIPort = 1633
+	// apiPortName is name of the container port exposing bee API.
+	apiPortName = "api"
+
+	// APIPortAnnotation overrides port of bee API of the pod.
+	APIPortAnnotation = "node-funder/api-port"
+	// APISchemeAnnotation overrides scheme of bee API of the pod.
+	APISchemeAnnotation = "node-funder/api-scheme"
+)
+
+// ErrInvalidAPIScheme is returned when bee API scheme is neither http nor https.
+var ErrInvalidAPIScheme = errors.New("api scheme must be http or https")
+
+// beeAPI resolves address of bee API of pods.
+type beeAPI struct {
+	port   int    // overrides port discovered from the pod spec, 0 means discover
+	scheme string // defaults to http
+}
+
+func newBeeAPI(cfg Config) (beeAPI, error) {
+	scheme := cfg.APIScheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	if err := validateAPIScheme(scheme); err != nil {
+		return beeAPI{}, err
+	}
+
+	if cfg.APIPort < 0 || cfg.APIPort > 65535 {
+		return beeAPI{}, fmt.Errorf("invalid api port %d", cfg.APIPort)
+	}
+
+	return beeAPI{port: cfg.APIPort, scheme: scheme}, nil
+}
+
+func validateAPIScheme(scheme string) error {
+	if scheme != "http" && scheme != "https" {
+		return fmt.Errorf("%w: %q", ErrInvalidAPIScheme, scheme)
+	}
+
+	return nil
+}
+
+// address returns address of bee API of the pod. The pod annotations take
+// precedence over the configured port and scheme, and the configured port
+// over the container port named "api".
+func (a beeAPI) address(pod *corev1.Pod) (string, error) {
+	scheme := a.scheme
+	if s, ok := pod.Annotations[APISchemeAnnotation]; ok {
+		if err := validateAPIScheme(s); err != nil {
+			return "", fmt.Errorf("annotation %s: %w", APISchemeAnnotation, err)
+		}
+
+		scheme = s
+	}
+
+	port, err := a.podPort(pod)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port))), nil
+}
+
+func (a beeAPI) podPort(pod *corev1.Pod) (int, error) {
+	if v, ok := pod.Annotations[APIPortAnnotation]; ok {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 || port > 65535 {
+			return 0, fmt.Errorf("annotation %s: invalid port %q", APIPortAnnotation, v)
+		}
+
+		return port, nil
+	}
+
+	if a.port != 0 {
+		return a.port, nil
+	}
+
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == apiPortName {
+				return int(p.ContainerPort), nil
+			}
+		}
+	}
+
+	return defaultAPIPort, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+)
+
+func Test_NodeInfoFromPod(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(annotations map[string]string, ports ...corev1.ContainerPort) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "bee-0", Namespace: "swarm", Annotations: annotations},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "bee", Ports: ports}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				PodIP: "10.0.0.1",
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+	apiPort := corev1.ContainerPort{Name: "api", ContainerPort: 8080}
+	p2pPort := corev1.ContainerPort{Name: "p2p", ContainerPort: 1634}
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		pod        *corev1.Pod
+		address    string
+		skipReason string
+	}{
+		{
+			name:    "default port",
+			pod:     newPod(nil, p2pPort),
+			address: "http://10.0.0.1:1633",
+		},
+		{
+			name:    "named port",
+			pod:     newPod(nil, p2pPort, apiPort),
+			address: "http://10.0.0.1:8080",
+		},
+		{
+			name:    "configured port and scheme",
+			cfg:     Config{APIPort: 443, APIScheme: "https"},
+			pod:     newPod(nil, apiPort),
+			address: "https://10.0.0.1:443",
+		},
+		{
+			name:    "annotations",
+			cfg:     Config{APIPort: 443},
+			pod:     newPod(map[string]string{APIPortAnnotation: "9443", APISchemeAnnotation: "https"}, apiPort),
+			address: "https://10.0.0.1:9443",
+		},
+		{
+			name:       "invalid port annotation",
+			pod:        newPod(map[string]string{APIPortAnnotation: "api"}),
+			skipReason: `annotation node-funder/api-port: invalid port "api"`,
+		},
+		{
+			name:       "invalid scheme annotation",
+			pod:        newPod(map[string]string{APISchemeAnnotation: "ftp"}),
+			skipReason: `annotation node-funder/api-scheme: api scheme must be http or https: "ftp"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			node, err := NodeInfoFromPod(tc.pod, tc.cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.address, node.Address)
+			assert.Equal(t, tc.skipReason, node.SkipReason)
+		})
+	}
+
+	t.Run("invalid scheme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NodeInfoFromPod(newPod(nil), Config{APIScheme: "ftp"})
+		assert.ErrorIs(t, err, ErrInvalidAPIScheme)
+	})
+}
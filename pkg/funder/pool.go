@@ -0,0 +1,33 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+// workerPool bounds the number of concurrently running jobs. Jobs must not
+// wait for other jobs submitted to the same pool, as that could deadlock.
+type workerPool struct {
+	sem chan struct{} // nil when number of jobs is not bounded
+}
+
+// newWorkerPool returns pool running at most size jobs at once. Size of zero
+// or less means no limit.
+func newWorkerPool(size int) *workerPool {
+	if size <= 0 {
+		return &workerPool{}
+	}
+
+	return &workerPool{sem: make(chan struct{}, size)}
+}
+
+// submit runs the job in a new goroutine once a worker is available.
+func (p *workerPool) submit(job func()) {
+	go func() {
+		if p.sem != nil {
+			p.sem <- struct{}{}
+			defer func() { <-p.sem }()
+		}
+
+		job()
+	}()
+}
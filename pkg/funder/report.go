@@ -0,0 +1,323 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+type WalletStatus string
+
+const (
+	WalletStatusFunded        WalletStatus = "funded"
+	WalletStatusAlreadyFunded WalletStatus = "already_funded"
+	WalletStatusSkipped       WalletStatus = "skipped"
+	WalletStatusFailed        WalletStatus = "failed"
+	// WalletStatusPartiallyFunded is status of a wallet topped up with one
+	// token, while top up with the other one failed or was skipped.
+	WalletStatusPartiallyFunded WalletStatus = "partially_funded"
+)
+
+// FundReport describes the outcome of a funding run. Amounts are in token
+// base units.
+type FundReport struct {
+	FundingAddress common.Address `json:"fundingAddress"`
+	NativeCoin     wallet.Token   `json:"-"`
+	SwarmToken     wallet.Token   `json:"-"`
+	Wallets        []WalletReport `json:"wallets"`
+	Totals         FundTotals     `json:"totals"`
+	// Namespaces groups totals of wallets by namespace of their nodes.
+	Namespaces map[string]FundTotals `json:"namespaces,omitempty"`
+	// SkippedNodes are nodes which were not funded because they are not ready.
+	SkippedNodes []SkippedNode `json:"skippedNodes,omitempty"`
+}
+
+// SkippedNode is a node skipped because it is not ready.
+type SkippedNode struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+func newSkippedNodes(nodes []NodeInfo) []SkippedNode {
+	result := make([]SkippedNode, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, SkippedNode{Name: n.Name, Namespace: n.Namespace, Reason: n.SkipReason})
+	}
+
+	return result
+}
+
+type FundTotals struct {
+	Wallets         int     `json:"wallets"`
+	Funded          int     `json:"funded"`
+	PartiallyFunded int     `json:"partiallyFunded"`
+	AlreadyFunded   int     `json:"alreadyFunded"`
+	Skipped         int     `json:"skipped"`
+	Failed          int     `json:"failed"`
+	Transferred     Amounts `json:"transferred"`
+}
+
+// WalletReport describes the outcome of funding a single wallet.
+type WalletReport struct {
+	Wallet        WalletInfo
+	Status        WalletStatus
+	BalanceBefore Amounts // nil amounts when balance could not be fetched
+	Transferred   Amounts
+	NativeTxHash  common.Hash
+	SwarmTxHash   common.Hash
+	Err           error // wraps NativeErr and SwarmErr when they are set
+	NativeErr     error // wraps ErrFailedFundingWithNativeToken
+	SwarmErr      error // wraps ErrFailedFundingWithSwarmToken
+}
+
+func newFundReport(fundingAddress common.Address, nativeCoin, swarmToken wallet.Token) FundReport {
+	return FundReport{
+		FundingAddress: fundingAddress,
+		NativeCoin:     nativeCoin,
+		SwarmToken:     swarmToken,
+		Wallets:        make([]WalletReport, 0),
+		Totals:         FundTotals{Transferred: newAmounts()},
+	}
+}
+
+func (r *FundReport) add(reports ...WalletReport) {
+	for _, wr := range reports {
+		r.Wallets = append(r.Wallets, wr)
+		r.Totals.add(wr)
+
+		namespace := wr.Wallet.Namespace
+		if namespace == "" {
+			continue
+		}
+
+		if r.Namespaces == nil {
+			r.Namespaces = make(map[string]FundTotals)
+		}
+
+		totals, ok := r.Namespaces[namespace]
+		if !ok {
+			totals = FundTotals{Transferred: newAmounts()}
+		}
+
+		totals.add(wr)
+		r.Namespaces[namespace] = totals
+	}
+}
+
+func (r *FundReport) addSkippedNodes(nodes ...NodeInfo) {
+	r.SkippedNodes = append(r.SkippedNodes, newSkippedNodes(nodes)...)
+}
+
+func (t *FundTotals) add(wr WalletReport) {
+	t.Wallets++
+
+	switch wr.Status {
+	case WalletStatusFunded:
+		t.Funded++
+	case WalletStatusPartiallyFunded:
+		t.PartiallyFunded++
+	case WalletStatusAlreadyFunded:
+		t.AlreadyFunded++
+	case WalletStatusSkipped:
+		t.Skipped++
+	case WalletStatusFailed:
+		t.Failed++
+	}
+
+	t.Transferred.NativeCoin.Add(t.Transferred.NativeCoin, wr.Transferred.NativeCoin)
+	t.Transferred.SwarmToken.Add(t.Transferred.SwarmToken, wr.Transferred.SwarmToken)
+}
+
+// WriteJSON writes the report as indented JSON document.
+func (r FundReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		FundReport
+		NativeCoin string `json:"nativeCoin"`
+		SwarmToken string `json:"swarmToken"`
+	}{
+		FundReport: r,
+		NativeCoin: r.NativeCoin.Symbol,
+		SwarmToken: r.SwarmToken.Symbol,
+	})
+}
+
+func (r WalletReport) MarshalJSON() ([]byte, error) {
+	type txHashes struct {
+		NativeCoin *common.Hash `json:"nativeCoin,omitempty"`
+		SwarmToken *common.Hash `json:"swarmToken,omitempty"`
+	}
+
+	report := struct {
+		Name          string       `json:"name"`
+		Namespace     string       `json:"namespace,omitempty"`
+		Address       string       `json:"address"`
+		ChainID       int64        `json:"chainID"`
+		Status        WalletStatus `json:"status"`
+		BalanceBefore Amounts      `json:"balanceBefore"`
+		Transferred   Amounts      `json:"transferred"`
+		TxHashes      txHashes     `json:"txHashes"`
+		Error         string       `json:"error,omitempty"`
+		NativeError   string       `json:"nativeError,omitempty"`
+		SwarmError    string       `json:"swarmError,omitempty"`
+	}{
+		Name:          r.Wallet.Name,
+		Namespace:     r.Wallet.Namespace,
+		Address:       r.Wallet.Address,
+		ChainID:       r.Wallet.ChainID,
+		Status:        r.Status,
+		BalanceBefore: r.BalanceBefore,
+		Transferred:   r.Transferred,
+		Error:         errorString(r.Err),
+		NativeError:   errorString(r.NativeErr),
+		SwarmError:    errorString(r.SwarmErr),
+	}
+
+	if r.NativeTxHash != (common.Hash{}) {
+		report.TxHashes.NativeCoin = &r.NativeTxHash
+	}
+
+	if r.SwarmTxHash != (common.Hash{}) {
+		report.TxHashes.SwarmToken = &r.SwarmTxHash
+	}
+
+	return json.Marshal(report)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+type StakeStatus string
+
+const (
+	StakeStatusStaked  StakeStatus = "staked"
+	StakeStatusSkipped StakeStatus = "skipped"
+	StakeStatusFailed  StakeStatus = "failed"
+)
+
+// StakeReport describes the outcome of a staking run. Amounts are in swarm
+// token base units.
+type StakeReport struct {
+	Nodes  []NodeStakeReport `json:"nodes"`
+	Totals StakeTotals       `json:"totals"`
+	// Namespaces groups totals of nodes by their namespace.
+	Namespaces map[string]StakeTotals `json:"namespaces,omitempty"`
+	// SkippedNodes are nodes which were not staked because they are not ready.
+	SkippedNodes []SkippedNode `json:"skippedNodes,omitempty"`
+}
+
+type StakeTotals struct {
+	Nodes   int      `json:"nodes"`
+	Staked  int      `json:"staked"`
+	Skipped int      `json:"skipped"`
+	Failed  int      `json:"failed"`
+	Amount  *big.Int `json:"amount"` // staked by all nodes in this run
+}
+
+// NodeStakeReport describes the outcome of staking a single node.
+type NodeStakeReport struct {
+	Node         NodeInfo
+	Status       StakeStatus
+	StakedBefore *big.Int // nil when stake info could not be fetched
+	Amount       *big.Int // staked in this run
+	Err          error
+}
+
+func newStakeReport() StakeReport {
+	return StakeReport{
+		Nodes:  make([]NodeStakeReport, 0),
+		Totals: StakeTotals{Amount: big.NewInt(0)},
+	}
+}
+
+func (r *StakeReport) add(reports ...NodeStakeReport) {
+	for _, nr := range reports {
+		r.Nodes = append(r.Nodes, nr)
+		r.Totals.add(nr)
+
+		namespace := nr.Node.Namespace
+		if namespace == "" {
+			continue
+		}
+
+		if r.Namespaces == nil {
+			r.Namespaces = make(map[string]StakeTotals)
+		}
+
+		totals, ok := r.Namespaces[namespace]
+		if !ok {
+			totals = StakeTotals{Amount: big.NewInt(0)}
+		}
+
+		totals.add(nr)
+		r.Namespaces[namespace] = totals
+	}
+}
+
+func (r *StakeReport) addSkippedNodes(nodes ...NodeInfo) {
+	r.SkippedNodes = append(r.SkippedNodes, newSkippedNodes(nodes)...)
+}
+
+func (t *StakeTotals) add(nr NodeStakeReport) {
+	t.Nodes++
+
+	switch nr.Status {
+	case StakeStatusStaked:
+		t.Staked++
+	case StakeStatusSkipped:
+		t.Skipped++
+	case StakeStatusFailed:
+		t.Failed++
+	}
+
+	t.Amount.Add(t.Amount, nr.Amount)
+}
+
+func (t StakeTotals) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Nodes   int     `json:"nodes"`
+		Staked  int     `json:"staked"`
+		Skipped int     `json:"skipped"`
+		Failed  int     `json:"failed"`
+		Amount  *string `json:"amount"`
+	}{
+		Nodes:   t.Nodes,
+		Staked:  t.Staked,
+		Skipped: t.Skipped,
+		Failed:  t.Failed,
+		Amount:  decimalString(t.Amount),
+	})
+}
+
+func (r NodeStakeReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name         string      `json:"name"`
+		Namespace    string      `json:"namespace,omitempty"`
+		Status       StakeStatus `json:"status"`
+		StakedBefore *string     `json:"stakedBefore"`
+		Amount       *string     `json:"amount"`
+		Error        string      `json:"error,omitempty"`
+	}{
+		Name:         r.Node.Name,
+		Namespace:    r.Node.Namespace,
+		Status:       r.Status,
+		StakedBefore: decimalString(r.StakedBefore),
+		Amount:       decimalString(r.Amount),
+		Error:        errorString(r.Err),
+	})
+}
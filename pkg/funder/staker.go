@@ -6,21 +6,31 @@ package funder
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
-	"net/http"
 	"strings"
 	"sync"
-	"sync/atomic"
 
-	"github.com/ethersphere/bee/v2/pkg/bigint"
 	"github.com/ethersphere/beekeeper/pkg/logging"
 	"github.com/ethersphere/node-funder/pkg/wallet"
 	"k8s.io/utils/strings/slices"
 )
 
-func Stake(ctx context.Context, cfg Config, nl NodeLister, options ...FunderOptions) error {
+// ErrFailedStaking is returned by Stake when staking of nodes fails.
+var ErrFailedStaking = errors.New("failed staking")
+
+// stakeToken is the swarm token nodes stake on any chain.
+var stakeToken = wallet.Token{
+	Symbol:   "BZZ",
+	Decimals: wallet.SwarmTokenDecimals,
+	BaseUnit: wallet.SwarmTokenBaseUnit,
+}
+
+// Stake tops up stake of bee nodes below the min amount. It returns an error
+// wrapping ErrFailedStaking when staking of any node fails, or when staking
+// of all nodes fails if cfg.AllowPartialStake is set.
+func Stake(ctx context.Context, cfg Config, nl NodeLister, options ...FunderOptions) (StakeReport, error) {
 	opts := DefaultOptions()
 	for _, opt := range options {
 		opt(opts)
@@ -32,95 +42,124 @@ func Stake(ctx context.Context, cfg Config, nl NodeLister, options ...FunderOpti
 	if nl == nil {
 		var err error
 
-		nl, err = newNodeLister()
+		nl, err = newNodeLister(cfg)
 		if err != nil {
-			return fmt.Errorf("create node lister: %w", err)
+			return StakeReport{}, fmt.Errorf("create node lister: %w", err)
 		}
 	}
 
-	nodes, err := nl.List(ctx, cfg.Namespace)
+	nodes, skipped, err := listBeeNodes(ctx, cfg, nl, opts.log)
 	if err != nil {
-		return fmt.Errorf("listing nodes failed: %w", err)
+		return StakeReport{}, err
 	}
 
-	nodes, omitted := filterBeeNodes(nodes)
+	policy, err := newTopUpPolicy(cfg.MinAmounts.SwarmToken, cfg.TargetAmounts.SwarmToken, stakeToken)
+	if err != nil {
+		return StakeReport{}, fmt.Errorf("invalid stake %w", err)
+	}
 
-	if len(omitted) > 0 {
-		opts.log.Infof("ignoring pods %v", omitted)
+	client, err := newAPIClient(cfg)
+	if err != nil {
+		return StakeReport{}, err
 	}
 
-	stakeAllNodes(ctx, nodes, cfg.MinAmounts.SwarmToken, opts.log)
+	report := newStakeReport()
+	report.addSkippedNodes(skipped...)
+	report.add(stakeAllNodes(ctx, client, nodes, policy, newWorkerPool(opts.concurrency), opts.log)...)
+
+	opts.metrics.observeStakeReport(report)
 
-	return nil
+	failed := report.Totals.Failed
+	if failed > 0 && (!cfg.AllowPartialStake || failed == report.Totals.Nodes) {
+		return report, fmt.Errorf("%w: %d of %d nodes", ErrFailedStaking, failed, report.Totals.Nodes)
+	}
+
+	return report, nil
 }
 
-func stakeAllNodes(ctx context.Context, nodes []NodeInfo, minVal float64, log logging.Logger) {
+func stakeAllNodes(ctx context.Context, client *apiClient, nodes []NodeInfo, policy topUpPolicy, pool *workerPool, log logging.Logger) []NodeStakeReport {
 	wg := sync.WaitGroup{}
 	wg.Add(len(nodes))
 
-	var skipped, staked atomic.Int32
+	reports := make([]NodeStakeReport, len(nodes))
 
-	for _, n := range nodes {
-		go func(node NodeInfo) {
+	for i, node := range nodes {
+		pool.submit(func() {
 			defer wg.Done()
 
-			si, err := fetchStakeInfo(ctx, node.Address)
-			if err != nil {
-				log.Infof("get stake info for node[%s] failed; reason: %s", node.Name, err)
-				return
-			}
+			reports[i] = stakeNodeReport(ctx, client, node, policy)
 
-			amount := calcTopUpAmount(minVal, si.StakedAmount, wallet.SwarmTokenDecimals)
-			if amount.Cmp(big.NewInt(0)) <= 0 {
-				skipped.Add(1)
+			switch r := reports[i]; r.Status {
+			case StakeStatusFailed:
+				log.Errorf("node[%s] - staking failed; reason: %s", node.Name, r.Err)
+			case StakeStatusSkipped:
 				log.Infof("node[%s] - already staked", node.Name)
-				// Top up is not needed, current stake value is sufficient
-				return
+			case StakeStatusStaked:
+				log.Infof("node[%s] - staked %s", node.Name, formatAmount(r.Amount, stakeToken.Decimals))
 			}
-
-			if err = stakeNode(ctx, node.Address, amount); err != nil {
-				log.Infof("node[%s] - staking failed; reason: %s", node.Name, err)
-			} else {
-				log.Infof("node[%s] - staked", node.Name)
-				staked.Add(1)
-			}
-		}(n)
+		})
 	}
 
 	wg.Wait()
 
-	log.Infof("staked %d", staked.Load())
-	log.Infof("skipped %d", skipped.Load())
-	log.Infof("failed %d", len(nodes)-int(staked.Load())-int(skipped.Load()))
-	log.Infof("total %d", len(nodes))
-}
+	var staked, skipped, failed int
 
-func stakeNode(ctx context.Context, nodeAddress string, amount *big.Int) error {
-	_, err := sendHTTPRequest(ctx, http.MethodPost, nodeAddress+"/stake/"+amount.String())
+	for _, r := range reports {
+		switch r.Status {
+		case StakeStatusStaked:
+			staked++
+		case StakeStatusSkipped:
+			skipped++
+		case StakeStatusFailed:
+			failed++
+		}
+	}
 
-	return err
-}
+	log.Infof("staked %d", staked)
+	log.Infof("skipped %d", skipped)
+	if failed > 0 {
+		log.Errorf("failed %d", failed)
+	} else {
+		log.Infof("failed %d", failed)
+	}
+	log.Infof("total %d", len(nodes))
 
-type stakeInfo struct {
-	StakedAmount *big.Int
+	return reports
 }
 
-func fetchStakeInfo(ctx context.Context, nodeAddress string) (stakeInfo, error) {
-	responseBytes, err := sendHTTPRequest(ctx, http.MethodGet, nodeAddress+"/stake")
+func stakeNodeReport(ctx context.Context, client *apiClient, node NodeInfo, policy topUpPolicy) NodeStakeReport {
+	report := NodeStakeReport{
+		Node:   node,
+		Status: StakeStatusFailed,
+		Amount: big.NewInt(0),
+	}
+
+	bc := client.node(node)
+
+	stakedAmount, err := bc.Stake(ctx)
 	if err != nil {
-		return stakeInfo{}, fmt.Errorf("get bee stake info failed: %w", err)
+		report.Err = fmt.Errorf("get stake info failed: %w", err)
+		return report
+	}
+
+	report.StakedBefore = stakedAmount
+
+	amount := calcTopUpAmount(policy, stakedAmount)
+	if amount.Cmp(big.NewInt(0)) <= 0 {
+		// Top up is not needed, current stake value is sufficient
+		report.Status = StakeStatusSkipped
+		return report
 	}
 
-	response := struct {
-		StakedAmount *bigint.BigInt `json:"stakedAmount"`
-	}{}
-	if err := json.Unmarshal(responseBytes, &response); err != nil {
-		return stakeInfo{}, fmt.Errorf("failed to unmarshal response :%w", err)
+	if err := bc.DepositStake(ctx, amount); err != nil {
+		report.Err = err
+		return report
 	}
 
-	return stakeInfo{
-		StakedAmount: response.StakedAmount.Int,
-	}, nil
+	report.Status = StakeStatusStaked
+	report.Amount = amount
+
+	return report
 }
 
 func filterBeeNodes(nodes []NodeInfo) ([]NodeInfo, []NodeInfo) {
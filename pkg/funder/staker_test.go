@@ -6,9 +6,13 @@ package funder_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/ethersphere/node-funder/pkg/funder"
 	fundermock "github.com/ethersphere/node-funder/pkg/funder/mock"
@@ -25,7 +29,7 @@ func Test_Stake(t *testing.T) {
 
 		cfg := Config{}
 		nl := fundermock.NewNodeLister(nil)
-		err := Stake(ctx, cfg, nl)
+		_, err := Stake(ctx, cfg, nl)
 		assert.NoError(t, err)
 	})
 
@@ -34,8 +38,32 @@ func Test_Stake(t *testing.T) {
 
 		nl := fundermock.NewNodeLister([]NodeInfo{{Name: "not-a-valid-beenode"}})
 		cfg := Config{Namespace: "swarm"}
-		err := Stake(ctx, cfg, nl)
+		report, err := Stake(ctx, cfg, nl)
 		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Totals.Nodes)
+
+		// field selector does not select bee nodes
+		cfg.FieldSelector = "status.phase=Running"
+		report, err = Stake(ctx, cfg, nl)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Totals.Nodes)
+
+		// nodes selected by label selector are not filtered by name
+		cfg.LabelSelector = "app=bee"
+		report, err = Stake(ctx, cfg, nl)
+		assert.ErrorIs(t, err, ErrFailedStaking)
+		assert.Equal(t, 1, report.Totals.Nodes)
+	})
+
+	t.Run("stake namespace - not ready node skipped", func(t *testing.T) {
+		t.Parallel()
+
+		nl := fundermock.NewNodeLister([]NodeInfo{{Name: "bee-0", Namespace: "swarm", SkipReason: "pod has no IP"}})
+		cfg := Config{Namespace: "swarm"}
+		report, err := Stake(ctx, cfg, nl)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Totals.Nodes)
+		assert.Equal(t, []SkippedNode{{Name: "bee-0", Namespace: "swarm", Reason: "pod has no IP"}}, report.SkippedNodes)
 	})
 
 	t.Run("stake namespace - valid bee node", func(t *testing.T) {
@@ -44,7 +72,7 @@ func Test_Stake(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			_, err := w.Write([]byte(`
 			{
-				"stakedAmount": "5",
+				"stakedAmount": "5"
 			}
 			`))
 			assert.NoError(t, err)
@@ -56,16 +84,139 @@ func Test_Stake(t *testing.T) {
 			t.Parallel()
 
 			cfg := Config{Namespace: "swarm"}
-			err := Stake(ctx, cfg, nl)
+			report, err := Stake(ctx, cfg, nl)
 			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Skipped)
+			assert.Equal(t, StakeStatusSkipped, report.Nodes[0].Status)
+			assert.Equal(t, "5", report.Nodes[0].StakedBefore.String())
 		})
 
 		t.Run("not staked", func(t *testing.T) {
 			t.Parallel()
 
-			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{SwarmToken: 20}}
-			err := Stake(ctx, cfg, nl)
+			cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{SwarmToken: "20"}}
+			report, err := Stake(ctx, cfg, nl)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Staked)
+			assert.Equal(t, StakeStatusStaked, report.Nodes[0].Status)
+			assert.Equal(t, "199999999999999995", report.Nodes[0].Amount.String())
+			assert.Equal(t, "199999999999999995", report.Totals.Amount.String())
+
+			data, err := json.Marshal(report)
 			assert.NoError(t, err)
+			assert.JSONEq(t, `{
+				"nodes": [{"name": "bee", "namespace": "swarm", "status": "staked", "stakedBefore": "5", "amount": "199999999999999995"}],
+				"totals": {"nodes": 1, "staked": 1, "skipped": 0, "failed": 0, "amount": "199999999999999995"},
+				"namespaces": {"swarm": {"nodes": 1, "staked": 1, "skipped": 0, "failed": 0, "amount": "199999999999999995"}}
+			}`, string(data))
+		})
+	})
+
+	t.Run("stake namespace - bounded concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		var inFlight, maxInFlight atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			_, err := w.Write([]byte(`{"stakedAmount": "5"}`))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(server.Close)
+
+		nodes := make([]NodeInfo, 6)
+		for i := range nodes {
+			nodes[i] = NodeInfo{Address: server.URL, Name: fmt.Sprintf("bee-%d", i)}
+		}
+		nl := fundermock.NewNodeLister(nodes)
+
+		cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{SwarmToken: "20"}}
+		report, err := Stake(ctx, cfg, nl, WithConcurrencyOption(2))
+		assert.NoError(t, err)
+		assert.Equal(t, 6, report.Totals.Staked)
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("stake namespace - failed", func(t *testing.T) {
+		t.Parallel()
+
+		okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, err := w.Write([]byte(`{"stakedAmount": "5"}`))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(okServer.Close)
+
+		failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodPost {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, err := w.Write([]byte(`{"stakedAmount": "5"}`))
+			assert.NoError(t, err)
+		}))
+		t.Cleanup(failingServer.Close)
+
+		cfg := Config{Namespace: "swarm", MinAmounts: MinAmounts{SwarmToken: "20"}}
+
+		t.Run("all nodes", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{{Address: failingServer.URL, Name: "bee-0"}})
+
+			report, err := Stake(ctx, cfg, nl)
+			assert.ErrorIs(t, err, ErrFailedStaking)
+			assert.Equal(t, 1, report.Totals.Failed)
+			assert.Error(t, report.Nodes[0].Err)
+
+			cfg := cfg
+			cfg.AllowPartialStake = true
+			_, err = Stake(ctx, cfg, nl)
+			assert.ErrorIs(t, err, ErrFailedStaking)
+		})
+
+		t.Run("some nodes", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{
+				{Address: failingServer.URL, Name: "bee-0"},
+				{Address: okServer.URL, Name: "bee-1"},
+			})
+
+			report, err := Stake(ctx, cfg, nl)
+			assert.ErrorIs(t, err, ErrFailedStaking)
+			assert.Equal(t, 1, report.Totals.Failed)
+			assert.Equal(t, 1, report.Totals.Staked)
+
+			cfg := cfg
+			cfg.AllowPartialStake = true
+			report, err = Stake(ctx, cfg, nl)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report.Totals.Failed)
+		})
+
+		t.Run("stake info not available", func(t *testing.T) {
+			t.Parallel()
+
+			nl := fundermock.NewNodeLister([]NodeInfo{{Address: "http://127.0.0.1:0", Name: "bee-0"}})
+
+			// the node is never reachable, retries would only slow the test down
+			cfg := cfg
+			cfg.APIRetries = -1
+
+			report, err := Stake(ctx, cfg, nl)
+			assert.ErrorIs(t, err, ErrFailedStaking)
+			assert.Nil(t, report.Nodes[0].StakedBefore)
 		})
 	})
 }
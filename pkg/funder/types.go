@@ -7,19 +7,33 @@ package funder
 import "fmt"
 
 type NamespaceNodes struct {
-	Name        string
-	NodeWallets []WalletInfo
+	Name          string
+	NodeWallets   []WalletInfo
+	FailedWallets []WalletReport // nodes whose wallet could not be fetched
+	SkippedNodes  []NodeInfo     // nodes which are not ready
 }
 
 type WalletInfo struct {
-	Name    string
-	Address string
-	ChainID int64
+	Name      string
+	Address   string
+	ChainID   int64
+	Namespace string // namespace of the node, empty for configured addresses
 }
 
 type NodeInfo struct {
-	Name    string
-	Address string
+	Name       string
+	Address    string
+	Namespace  string
+	SkipReason string // why the node is not ready, empty when it is ready
+}
+
+// key identifies the node across namespaces.
+func (n NodeInfo) key() string {
+	if n.Namespace == "" {
+		return n.Name
+	}
+
+	return n.Namespace + "/" + n.Name
 }
 
 func NewWalletInfo(name, address string, chainID int64) WalletInfo {
@@ -0,0 +1,455 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+)
+
+const (
+	// nodeRetryDelay is how long WatchNodes waits before it funds a node
+	// whose API did not respond again.
+	nodeRetryDelay = 5 * time.Second
+	// maxNodeAttempts is how many times WatchNodes tries to fund a node
+	// whose API does not respond.
+	maxNodeAttempts = 5
+)
+
+var (
+	// ErrInvalidInterval is returned by Watch when the interval is not positive.
+	ErrInvalidInterval = errors.New("watch interval must be positive")
+	// ErrNamespaceNotSet is returned by WatchNodes when no namespace is configured.
+	ErrNamespaceNotSet = errors.New("namespace must be set")
+	// ErrWatchStopped is returned by WatchNodes when the node watch ends
+	// before the context is canceled.
+	ErrWatchStopped = errors.New("node watch stopped")
+)
+
+// Watch keeps wallets funded by running Fund every cfg.Interval until ctx is
+// canceled. The funding wallet and node lister are created once and reused by
+// all rounds. Errors of a round are logged and passed to onRound, when set,
+// together with the round report.
+//
+// Wallets whose transfers sent by a previous round are still waiting to be
+// mined are skipped until the transfers are mined or dropped, so they are not
+// topped up twice.
+//
+// When ctx is canceled, the round in progress starts no new transfers, and
+// transfers it already sent are given cfg.ShutdownTimeout to be mined.
+// Transfers still waiting to be mined after that are abandoned and reported as
+// failed with their transaction hash.
+func Watch(
+	ctx context.Context,
+	cfg Config,
+	nl NodeLister,
+	fundingWallet *wallet.Wallet,
+	onRound func(FundReport, error),
+	options ...FunderOptions,
+) error {
+	var err error
+
+	if cfg.Interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if fundingWallet == nil {
+		fundingWallet, err = makeFundingWallet(ctx, cfg, opts.metrics.walletOptions()...)
+		if err != nil {
+			return fmt.Errorf("make funding wallet: %w", err)
+		}
+
+		defer fundingWallet.Close()
+	}
+
+	if nl == nil && cfg.hasNamespaces() {
+		nl, err = newNodeLister(cfg)
+		if err != nil {
+			return fmt.Errorf("make node lister: %w", err)
+		}
+	}
+
+	// transfers left pending by a round are not repeated by later rounds
+	options = append(options[:len(options):len(options)], withPendingTransfers(newPendingTransfers()))
+
+	opts.log.Infof("watching wallets, funding every %s", cfg.Interval)
+	defer opts.log.Info("watching wallets stopped")
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := fundRound(ctx, cfg, nl, fundingWallet, options)
+		if err != nil {
+			opts.log.Errorf("funding round failed: %v", err)
+		}
+
+		if onRound != nil {
+			onRound(report, err)
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fundRound runs Fund with ctx, while receipts of its transfers are awaited
+// with context which outlives ctx by cfg.ShutdownTimeout, so a shutdown does
+// not interrupt transfers which are about to be mined.
+func fundRound(
+	ctx context.Context,
+	cfg Config,
+	nl NodeLister,
+	fundingWallet *wallet.Wallet,
+	options []FunderOptions,
+) (FundReport, error) {
+	receiptCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-receiptCtx.Done():
+			return
+		}
+
+		timer := time.NewTimer(cfg.ShutdownTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-receiptCtx.Done():
+		}
+	}()
+
+	return Fund(wallet.WithReceiptContext(ctx, receiptCtx), cfg, nl, fundingWallet, options...)
+}
+
+// WatchNodes funds nodes of the namespace as soon as their pods become ready,
+// and stakes them up to amounts of stakeCfg when it is set. Pod events of a node are
+// debounced by cfg.WatchDebounce, so a restarting pod is funded once it
+// settles, and funding of a node never overlaps with funding of the same
+// node. At most the concurrency option of nodes are funded at once. Nodes
+// whose API does not respond yet are retried a few times.
+// Funding errors are logged. Limits of cfg.MaxTotal apply to transfers to all
+// nodes funded until the watch ends, not to each funding.
+func WatchNodes(
+	ctx context.Context,
+	cfg Config,
+	stakeCfg *Config,
+	nw NodeWatcher,
+	fundingWallet *wallet.Wallet,
+	options ...FunderOptions,
+) error {
+	var err error
+
+	if !cfg.hasNamespaces() {
+		return ErrNamespaceNotSet
+	}
+
+	if err := cfg.validateNamespaces(); err != nil {
+		return err
+	}
+
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if fundingWallet == nil {
+		fundingWallet, err = makeFundingWallet(ctx, cfg, opts.metrics.walletOptions()...)
+		if err != nil {
+			return fmt.Errorf("make funding wallet: %w", err)
+		}
+
+		defer fundingWallet.Close()
+	}
+
+	nativeCoin, swarmToken, err := fundingTokens(ctx, fundingWallet)
+	if err != nil {
+		return err
+	}
+
+	caps, err := makeSpendingCaps(cfg, nativeCoin, swarmToken)
+	if err != nil {
+		return err
+	}
+
+	// fundings of single nodes share the caps and pending transfers
+	options = append(options[:len(options):len(options)], withSpendingCaps(caps), withPendingTransfers(newPendingTransfers()))
+
+	if nw == nil {
+		nw, err = newNodeWatcher(cfg)
+		if err != nil {
+			return fmt.Errorf("make node watcher: %w", err)
+		}
+	}
+
+	// canceled on return, so fundings, timers and watches do not outlive the
+	// function when the node watch stops on its own
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	nodeC, err := watchNamespaces(ctx, nw, cfg.namespaces())
+	if err != nil {
+		return fmt.Errorf("watching nodes failed: %w", err)
+	}
+
+	opts.log.Infof("watching nodes in namespace=%s", cfg.namespacesString())
+	defer opts.log.Info("watching nodes stopped")
+
+	type (
+		dueNode struct {
+			node NodeInfo
+			seq  uint64
+		}
+		nodeTimer struct {
+			timer *time.Timer
+			seq   uint64
+		}
+		fundResult struct {
+			node  NodeInfo
+			retry bool
+		}
+	)
+
+	var (
+		wg       sync.WaitGroup
+		pool     = newWorkerPool(opts.concurrency) // bounds fundings of all nodes
+		seq      uint64
+		dueC     = make(chan dueNode)
+		resultC  = make(chan fundResult)
+		timers   = make(map[string]nodeTimer) // pending fundings by node
+		busy     = make(map[string]bool)      // fundings in progress by node
+		attempts = make(map[string]int)       // failed fundings of nodes retried later
+	)
+
+	// schedule funds the node after delay, unless it is scheduled again before
+	schedule := func(node NodeInfo, delay time.Duration) {
+		if t, ok := timers[node.key()]; ok {
+			t.timer.Stop()
+		}
+
+		seq++
+		due := dueNode{node: node, seq: seq}
+
+		timers[node.key()] = nodeTimer{
+			timer: time.AfterFunc(delay, func() {
+				select {
+				case dueC <- due:
+				case <-ctx.Done():
+				}
+			}),
+			seq: seq,
+		}
+	}
+
+	defer func() {
+		for _, t := range timers {
+			t.timer.Stop()
+		}
+
+		cancel()
+		wg.Wait()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case node, ok := <-nodeC:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return ErrWatchStopped
+			}
+
+			opts.log.Debugf("node[%s] - pod ready", node.Name)
+
+			attempts[node.key()] = 0
+			schedule(node, cfg.WatchDebounce)
+		case due := <-dueC:
+			node, key := due.node, due.node.key()
+
+			// timer fired before it was replaced by a later one
+			if timers[key].seq != due.seq {
+				continue
+			}
+
+			delete(timers, key)
+
+			if busy[key] {
+				schedule(node, cfg.WatchDebounce)
+				continue
+			}
+
+			busy[key] = true
+
+			wg.Add(1)
+			pool.submit(func() {
+				defer wg.Done()
+
+				// the watch ended while the funding waited for a worker
+				if ctx.Err() != nil {
+					return
+				}
+
+				retry := fundNode(ctx, cfg, stakeCfg, node, fundingWallet, opts.log, options)
+
+				select {
+				case resultC <- fundResult{node: node, retry: retry}:
+				case <-ctx.Done():
+				}
+			})
+		case res := <-resultC:
+			key := res.node.key()
+			delete(busy, key)
+
+			if !res.retry {
+				delete(attempts, key)
+				continue
+			}
+
+			attempts[key]++
+			if attempts[key] >= maxNodeAttempts {
+				opts.log.Errorf("node[%s] - funding skipped; node API not available after %d attempts", key, attempts[key])
+				delete(attempts, key)
+
+				continue
+			}
+
+			schedule(res.node, max(cfg.WatchDebounce, nodeRetryDelay))
+		}
+	}
+}
+
+// fundNode funds and stakes a single node. It reports whether the node should
+// be funded again later because its API did not respond.
+func fundNode(
+	ctx context.Context,
+	cfg Config,
+	stakeCfg *Config,
+	node NodeInfo,
+	fundingWallet *wallet.Wallet,
+	log logging.Logger,
+	options []FunderOptions,
+) bool {
+	nl := staticNodeLister{node}
+
+	// fund the node in its namespace only
+	cfg.Namespace, cfg.Namespaces, cfg.AllNamespaces = node.Namespace, nil, false
+
+	report, err := Fund(ctx, cfg, nl, fundingWallet, options...)
+	if walletNotFetched(report) {
+		// wallet info could not be fetched from the node
+		return true
+	}
+
+	if err != nil {
+		log.Errorf("node[%s] - funding failed: %v", node.Name, err)
+		return false
+	}
+
+	if stakeCfg == nil {
+		return false
+	}
+
+	// stake with the node API access of the funding, only amounts differ
+	stake := cfg
+	stake.MinAmounts, stake.TargetAmounts = stakeCfg.MinAmounts, stakeCfg.TargetAmounts
+
+	if _, err := Stake(ctx, stake, nl, options...); err != nil {
+		log.Errorf("node[%s] - staking failed: %v", node.Name, err)
+	}
+
+	return false
+}
+
+// walletNotFetched reports whether wallet of a node of the report could not be
+// fetched.
+func walletNotFetched(report FundReport) bool {
+	for _, wr := range report.Wallets {
+		if errors.Is(wr.Err, ErrFailedFetchingWallet) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watchNamespaces merges nodes watched in all namespaces into one channel,
+// closed once watches of all namespaces end. Watches already started are
+// stopped when watch of a namespace fails.
+func watchNamespaces(ctx context.Context, nw NodeWatcher, namespaces []string) (<-chan NodeInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		wg    sync.WaitGroup
+		nodeC = make(chan NodeInfo)
+	)
+
+	for _, namespace := range namespaces {
+		c, err := nw.Watch(ctx, namespace)
+		if err != nil {
+			cancel()
+			wg.Wait()
+
+			return nil, fmt.Errorf("namespace %q: %w", namespace, err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for node := range c {
+				if node.Namespace == "" {
+					node.Namespace = namespace
+				}
+
+				select {
+				case nodeC <- node:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer cancel()
+
+		wg.Wait()
+		close(nodeC)
+	}()
+
+	return nodeC, nil
+}
+
+// staticNodeLister lists the same nodes in any namespace.
+type staticNodeLister []NodeInfo
+
+func (nl staticNodeLister) List(context.Context, string) ([]NodeInfo, error) {
+	return nl, nil
+}
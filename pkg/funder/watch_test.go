@@ -0,0 +1,488 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package funder_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/ethersphere/node-funder/pkg/funder"
+	fundermock "github.com/ethersphere/node-funder/pkg/funder/mock"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	walletmock "github.com/ethersphere/node-funder/pkg/wallet/mock"
+)
+
+func Test_Watch(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+	nl := fundermock.NewNodeLister(nil)
+	cfg := Config{
+		Addresses:  []string{"0x95f8916183f7C7154e49396507F5b0FafA4d8077"},
+		MinAmounts: MinAmounts{NativeCoin: "3"},
+	}
+
+	t.Run("invalid interval", func(t *testing.T) {
+		t.Parallel()
+
+		w := wallet.New(newFundedBackendClient(t, key), key)
+		err := Watch(context.Background(), cfg, nl, w, nil)
+		assert.ErrorIs(t, err, ErrInvalidInterval)
+	})
+
+	t.Run("funds every interval", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		w := wallet.New(newFundedBackendClient(t, key), key)
+
+		cfg := cfg
+		cfg.Interval = 10 * time.Millisecond
+
+		var (
+			mu      sync.Mutex
+			reports []FundReport
+		)
+
+		err := Watch(ctx, cfg, nl, w, func(report FundReport, err error) {
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			reports = append(reports, report)
+			if len(reports) == 3 {
+				cancel()
+			}
+		})
+		assert.NoError(t, err)
+		assert.Len(t, reports, 3)
+
+		for _, r := range reports {
+			assert.Equal(t, 1, r.Totals.Funded)
+		}
+	})
+
+	t.Run("shutdown abandons pending transfers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		bc := newFundedBackendClient(t, key, walletmock.WithReceiptOutcome(walletmock.ReceiptPending))
+		w := wallet.New(bc, key,
+			wallet.WithReceiptTimeoutOption(time.Hour),
+			wallet.WithPollIntervalOption(5*time.Millisecond),
+		)
+
+		cfg := cfg
+		cfg.Interval = time.Hour
+		cfg.ShutdownTimeout = 20 * time.Millisecond
+
+		var (
+			report   FundReport
+			roundErr error
+		)
+
+		start := time.Now()
+		err := Watch(ctx, cfg, nl, w, func(r FundReport, err error) {
+			report, roundErr = r, err
+		})
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Minute)
+		assert.Error(t, roundErr)
+		assert.Equal(t, 1, report.Totals.Failed)
+		assert.ErrorIs(t, report.Wallets[0].NativeErr, context.Canceled)
+	})
+
+	t.Run("pending transfer not repeated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var sent atomic.Int32
+
+		bc := newFundedBackendClient(t, key,
+			walletmock.WithReceiptOutcome(walletmock.ReceiptPending),
+			walletmock.WithSendTransaction(func(*types.Transaction) error {
+				sent.Add(1)
+				return nil
+			}),
+		)
+		w := wallet.New(bc, key,
+			wallet.WithReceiptTimeoutOption(20*time.Millisecond),
+			wallet.WithPollIntervalOption(5*time.Millisecond),
+		)
+
+		cfg := cfg
+		cfg.Interval = 10 * time.Millisecond
+
+		var (
+			reports []FundReport
+			errs    []error
+		)
+
+		err := Watch(ctx, cfg, nl, w, func(report FundReport, err error) {
+			reports, errs = append(reports, report), append(errs, err)
+			if len(reports) == 3 {
+				cancel()
+			}
+		})
+		assert.NoError(t, err)
+		assert.Len(t, reports, 3)
+		assert.Equal(t, int32(1), sent.Load())
+
+		assert.Error(t, errs[0])
+		assert.Equal(t, 1, reports[0].Totals.Failed)
+		assert.ErrorIs(t, reports[0].Wallets[0].NativeErr, wallet.ErrTransactionPending)
+
+		// the wallet is skipped while its transfer is pending
+		for i := 1; i < 3; i++ {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, 1, reports[i].Totals.Skipped)
+			assert.ErrorIs(t, reports[i].Wallets[0].Err, ErrTransferPending)
+		}
+	})
+
+	t.Run("shutdown starts no new transfers", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var sent atomic.Int32
+
+		bc := newFundedBackendClient(t, key, walletmock.WithSendTransaction(func(*types.Transaction) error {
+			sent.Add(1)
+			return nil
+		}))
+		w := wallet.New(bc, key)
+
+		cfg := cfg
+		cfg.Interval = time.Hour
+		cfg.ShutdownTimeout = time.Hour
+
+		var roundErr error
+
+		err := Watch(ctx, cfg, nl, w, func(_ FundReport, err error) {
+			roundErr = err
+		})
+		assert.NoError(t, err)
+		assert.Error(t, roundErr)
+		assert.Zero(t, sent.Load())
+	})
+}
+
+func Test_WatchNodes(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+
+	var walletRequests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/wallet" {
+			walletRequests.Add(1)
+		}
+
+		_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeC := make(chan NodeInfo)
+	nw := fundermock.NewNodeWatcher(nodeC)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	cfg := Config{
+		Namespace:     "swarm",
+		MinAmounts:    MinAmounts{NativeCoin: "3"},
+		WatchDebounce: 20 * time.Millisecond,
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- WatchNodes(ctx, cfg, nil, nw, w)
+	}()
+
+	node := NodeInfo{Name: "bee-0", Address: server.URL}
+
+	// pod restarts are debounced into a single funding
+	for i := 0; i < 3; i++ {
+		nodeC <- node
+	}
+
+	assert.Eventually(t, func() bool { return walletRequests.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	nodeC <- node
+	assert.Eventually(t, func() bool { return walletRequests.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errC)
+
+	// fundings of debounced pod restarts would have been done by now
+	assert.Equal(t, int32(2), walletRequests.Load())
+}
+
+func Test_WatchNodes_concurrency(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+
+	var requests, inFlight, maxInFlight atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		requests.Add(1)
+
+		_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeC := make(chan NodeInfo)
+	nw := fundermock.NewNodeWatcher(nodeC)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	cfg := Config{
+		Namespace:     "swarm",
+		WatchDebounce: time.Millisecond,
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- WatchNodes(ctx, cfg, nil, nw, w, WithConcurrencyOption(2))
+	}()
+
+	for i := 0; i < 6; i++ {
+		nodeC <- NodeInfo{Name: fmt.Sprintf("bee-%d", i), Address: server.URL}
+	}
+
+	// bee API of every node is requested once by its funding
+	assert.Eventually(t, func() bool { return requests.Load() == 6 }, 5*time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errC)
+	assert.Equal(t, int32(2), maxInFlight.Load())
+}
+
+func Test_WatchNodes_maxTotal(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeC := make(chan NodeInfo)
+	nw := fundermock.NewNodeWatcher(nodeC)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	m := NewMetrics()
+	cfg := Config{
+		Namespace:     "swarm",
+		MinAmounts:    MinAmounts{NativeCoin: "3"},
+		MaxTotal:      MaxAmounts{NativeCoin: "3"},
+		WatchDebounce: time.Millisecond,
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- WatchNodes(ctx, cfg, nil, nw, w, WithMetricsOption(m))
+	}()
+
+	// each node is topped up by 2, so the second transfer exceeds the limit
+	nodeC <- NodeInfo{Name: "bee-0", Address: server.URL}
+	nodeC <- NodeInfo{Name: "bee-1", Address: server.URL}
+
+	// native and swarm balances of both nodes are recorded when fundings end
+	assert.Eventually(t, func() bool { return testutil.CollectAndCount(m.NodeBalance) == 4 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errC)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.Transfers.WithLabelValues("native", "sent")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.TransferredAmount.WithLabelValues("native")))
+}
+
+func Test_WatchNodes_stake(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+
+	var stakeRequests, unauthorized atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			unauthorized.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if req.URL.Path == "/stake" {
+			stakeRequests.Add(1)
+		}
+
+		_, err := w.Write([]byte(`{"walletAddress": "0x95f8916183f7C7154e49396507F5b0FafA4d8077", "chainID": 100, "stakedAmount": "100000000000000000"}`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nodeC := make(chan NodeInfo)
+	nw := fundermock.NewNodeWatcher(nodeC)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	cfg := Config{
+		Namespace:     "swarm",
+		APIToken:      "secret",
+		MinAmounts:    MinAmounts{NativeCoin: "3"},
+		WatchDebounce: time.Millisecond,
+	}
+	stakeCfg := Config{MinAmounts: MinAmounts{SwarmToken: "5"}}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- WatchNodes(ctx, cfg, &stakeCfg, nw, w)
+	}()
+
+	nodeC <- NodeInfo{Name: "bee-0", Address: server.URL}
+
+	// staking uses the bee API access of the funding
+	assert.Eventually(t, func() bool { return stakeRequests.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-errC)
+	assert.Equal(t, int32(0), unauthorized.Load())
+}
+
+func Test_WatchNodes_watchStopped(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+
+	requestC := make(chan struct{})
+	release := make(chan struct{})
+
+	// bee API blocks until the watch has stopped
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case requestC <- struct{}{}:
+		case <-req.Context().Done():
+		}
+
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	nodeC := make(chan NodeInfo)
+	nw := fundermock.NewNodeWatcher(nodeC)
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	cfg := Config{
+		Namespace:     "swarm",
+		MinAmounts:    MinAmounts{NativeCoin: "3"},
+		WatchDebounce: time.Millisecond,
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- WatchNodes(context.Background(), cfg, nil, nw, w)
+	}()
+
+	nodeC <- NodeInfo{Name: "bee-0", Address: server.URL}
+	<-requestC
+
+	// a pending funding does not block the return either
+	nodeC <- NodeInfo{Name: "bee-1", Address: server.URL}
+	close(nodeC)
+
+	select {
+	case err := <-errC:
+		assert.ErrorIs(t, err, ErrWatchStopped)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchNodes did not return after the node watch stopped")
+	}
+}
+
+// failingNodeWatcher fails to watch the namespace, and reports watches of
+// other namespaces stopped.
+type failingNodeWatcher struct {
+	namespace string
+	stoppedC  chan string
+}
+
+func (nw *failingNodeWatcher) Watch(ctx context.Context, namespace string) (<-chan NodeInfo, error) {
+	if namespace == nw.namespace {
+		return nil, errors.New("forbidden")
+	}
+
+	nodeC := make(chan NodeInfo)
+
+	go func() {
+		<-ctx.Done()
+		close(nodeC)
+		nw.stoppedC <- namespace
+	}()
+
+	return nodeC, nil
+}
+
+func Test_WatchNodes_watchFailed(t *testing.T) {
+	t.Parallel()
+
+	key := generateKey(t)
+	nw := &failingNodeWatcher{namespace: "swarm-2", stoppedC: make(chan string, 1)}
+	w := wallet.New(newFundedBackendClient(t, key), key)
+	cfg := Config{Namespaces: []string{"swarm", "swarm-2"}}
+
+	err := WatchNodes(context.Background(), cfg, nil, nw, w)
+	assert.ErrorContains(t, err, `namespace "swarm-2": forbidden`)
+
+	select {
+	case namespace := <-nw.stoppedC:
+		assert.Equal(t, "swarm", namespace)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch of namespace swarm was not stopped")
+	}
+}
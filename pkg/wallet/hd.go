@@ -0,0 +1,145 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDerivationPath is BIP-44 path of Ethereum accounts, index of the
+// account is appended to it.
+const DefaultDerivationPath = "m/44'/60'/0'/0"
+
+// hardenedOffset is offset of hardened child indexes of derivation paths.
+const hardenedOffset = 0x80000000
+
+var (
+	errInvalidChildKey = errors.New("invalid child key")
+
+	// ErrInvalidIndex is returned when account index is not a non-hardened
+	// child index, i.e. it is not below 2^31.
+	ErrInvalidIndex = errors.New("invalid account index")
+)
+
+// HDWallet derives keys from a BIP-39 mnemonic by BIP-32 derivation paths.
+type HDWallet struct {
+	seed []byte
+}
+
+// NewHDWallet returns wallet of the mnemonic, protected by the optional
+// passphrase.
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+
+	return &HDWallet{seed: seed}, nil
+}
+
+// ReadMnemonicFile reads mnemonic from the file.
+func ReadMnemonicFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read mnemonic file: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Key returns key of the account with the index under the base path, e.g.
+// DefaultDerivationPath.
+func (w *HDWallet) Key(basePath string, index uint32) (Key, error) {
+	if index >= hardenedOffset {
+		return "", fmt.Errorf("%w: %d", ErrInvalidIndex, index)
+	}
+
+	path, err := accounts.ParseDerivationPath(basePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid derivation path %q: %w", basePath, err)
+	}
+
+	path = append(path, index)
+
+	key, chainCode := hmacSHA512([]byte("Bitcoin seed"), w.seed)
+
+	var k btcec.ModNScalar
+	if overflow := k.SetByteSlice(key); overflow || k.IsZero() {
+		return "", errInvalidChildKey
+	}
+
+	for _, i := range path {
+		var data []byte
+		if i >= hardenedOffset {
+			kb := k.Bytes()
+			data = append([]byte{0}, kb[:]...)
+		} else {
+			data = btcec.PrivKeyFromScalar(&k).PubKey().SerializeCompressed()
+		}
+
+		data = binary.BigEndian.AppendUint32(data, i)
+
+		var child btcec.ModNScalar
+
+		key, chainCode = hmacSHA512(chainCode, data)
+		if overflow := child.SetByteSlice(key); overflow {
+			return "", fmt.Errorf("%w at %s", errInvalidChildKey, path)
+		}
+
+		if k.Add(&child); k.IsZero() {
+			return "", fmt.Errorf("%w at %s", errInvalidChildKey, path)
+		}
+	}
+
+	kb := k.Bytes()
+
+	return Key(hex.EncodeToString(kb[:])), nil
+}
+
+// Addresses returns addresses of count accounts under the base path,
+// starting with the index.
+func (w *HDWallet) Addresses(basePath string, index, count uint32) ([]common.Address, error) {
+	if uint64(index)+uint64(count) > hardenedOffset {
+		return nil, fmt.Errorf("%w: %d accounts from %d", ErrInvalidIndex, count, index)
+	}
+
+	addresses := make([]common.Address, 0, count)
+
+	for i := range count {
+		key, err := w.Key(basePath, index+i)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := key.PublicAddress()
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+func hmacSHA512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	return sum[:32], sum[32:]
+}
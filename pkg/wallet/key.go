@@ -8,7 +8,10 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -58,3 +61,34 @@ func GenerateKey() (Key, error) {
 
 	return Key(keyStr), nil
 }
+
+// ReadKeyFile reads hex encoded key, optionally prefixed with 0x, from the file.
+func ReadKeyFile(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read key file: %w", err)
+	}
+
+	key := Key(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if _, err := key.PrivateECDSA(); err != nil {
+		return "", fmt.Errorf("invalid key in key file %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// ReadKeystore decrypts key of the go-ethereum V3 keystore file with the
+// password.
+func ReadKeystore(path, password string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read keystore: %w", err)
+	}
+
+	k, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return "", fmt.Errorf("decrypt keystore %s: %w", path, err)
+	}
+
+	return Key(hex.EncodeToString(crypto.FromECDSA(k.PrivateKey))), nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeySource provides key of a wallet.
+type KeySource interface {
+	Key() (Key, error)
+}
+
+// KeySourceFunc is KeySource of a function.
+type KeySourceFunc func() (Key, error)
+
+func (f KeySourceFunc) Key() (Key, error) {
+	return f()
+}
+
+// HexKeySource returns source of the hex encoded key, optionally prefixed
+// with 0x.
+func HexKeySource(hex string) KeySource {
+	return KeySourceFunc(func() (Key, error) {
+		key := Key(strings.TrimPrefix(strings.TrimSpace(hex), "0x"))
+		if _, err := key.PrivateECDSA(); err != nil {
+			return "", fmt.Errorf("invalid key: %w", err)
+		}
+
+		return key, nil
+	})
+}
+
+// FileKeySource returns source of the hex encoded key in the file.
+func FileKeySource(path string) KeySource {
+	return KeySourceFunc(func() (Key, error) {
+		return ReadKeyFile(path)
+	})
+}
+
+// KeystoreKeySource returns source of the key in go-ethereum V3 keystore file,
+// encrypted with the password.
+func KeystoreKeySource(path, password string) KeySource {
+	return KeySourceFunc(func() (Key, error) {
+		return ReadKeystore(path, password)
+	})
+}
+
+// EnvKeySource returns source of the hex encoded key in the environment
+// variable.
+func EnvKeySource(name string) KeySource {
+	return KeySourceFunc(func() (Key, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		key, err := HexKeySource(value).Key()
+		if err != nil {
+			return "", fmt.Errorf("environment variable %s: %w", name, err)
+		}
+
+		return key, nil
+	})
+}
+
+// MnemonicKeySource returns source of the key of the account with the index
+// under the base path, derived from the mnemonic and its passphrase.
+func MnemonicKeySource(mnemonic, passphrase, basePath string, index uint32) KeySource {
+	return KeySourceFunc(func() (Key, error) {
+		hd, err := NewHDWallet(mnemonic, passphrase)
+		if err != nil {
+			return "", err
+		}
+
+		return hd.Key(basePath, index)
+	})
+}
+
+// GeneratedKeySource returns source of a new random key on every call. It is
+// meant for tests and local clusters, as the key is not stored anywhere and
+// funds sent to its address are lost with it.
+func GeneratedKeySource() KeySource {
+	return KeySourceFunc(GenerateKey)
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "funder"
+	metricsSubsystem = "wallet"
+)
+
+// Metrics are wallet prometheus metrics. They are not registered, use
+// Collectors to register them in a registry.
+type Metrics struct {
+	RPCCallDuration *prometheus.HistogramVec
+	RPCCallErrors   *prometheus.CounterVec
+	NonceResets     prometheus.Counter
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		RPCCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "rpc_call_duration_seconds",
+			Help:      "Duration of chain node RPC calls.",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"method"}),
+		RPCCallErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "rpc_call_errors_total",
+			Help:      "Number of failed chain node RPC calls.",
+		}, []string{"method"}),
+		NonceResets: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "nonce_resets_total",
+			Help:      "Number of times the cached nonce was reset after a transaction was rejected.",
+		}),
+	}
+}
+
+// Collectors returns all metrics collectors.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.RPCCallDuration,
+		m.RPCCallErrors,
+		m.NonceResets,
+	}
+}
+
+func (m *Metrics) nonceReset() {
+	if m != nil {
+		m.NonceResets.Inc()
+	}
+}
+
+// instrumentedClient is BackendClient observing durations of calls.
+type instrumentedClient struct {
+	client  BackendClient
+	metrics *Metrics
+}
+
+func newInstrumentedClient(client BackendClient, m *Metrics) BackendClient {
+	return &instrumentedClient{client: client, metrics: m}
+}
+
+// observe records the call of the method started at start. It is meant to be
+// deferred with pointer to the named error result of the call. Not found
+// results, e.g. receipts of transactions not mined yet, are not errors.
+func (c *instrumentedClient) observe(method string, start time.Time, err *error) {
+	c.metrics.RPCCallDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+	if *err != nil && !errors.Is(*err, ethereum.NotFound) {
+		c.metrics.RPCCallErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// Close closes the underlying client, when it supports closing.
+func (c *instrumentedClient) Close() {
+	if cl, ok := c.client.(interface{ Close() }); ok {
+		cl.Close()
+	}
+}
+
+func (c *instrumentedClient) ChainID(ctx context.Context) (_ *big.Int, err error) {
+	defer c.observe("eth_chainId", time.Now(), &err)
+	return c.client.ChainID(ctx)
+}
+
+func (c *instrumentedClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (_ []byte, err error) {
+	defer c.observe("eth_call", time.Now(), &err)
+	return c.client.CallContract(ctx, call, blockNumber)
+}
+
+func (c *instrumentedClient) PendingNonceAt(ctx context.Context, account common.Address) (_ uint64, err error) {
+	defer c.observe("eth_getTransactionCount", time.Now(), &err)
+	return c.client.PendingNonceAt(ctx, account)
+}
+
+func (c *instrumentedClient) SuggestGasPrice(ctx context.Context) (_ *big.Int, err error) {
+	defer c.observe("eth_gasPrice", time.Now(), &err)
+	return c.client.SuggestGasPrice(ctx)
+}
+
+func (c *instrumentedClient) SuggestGasTipCap(ctx context.Context) (_ *big.Int, err error) {
+	defer c.observe("eth_maxPriorityFeePerGas", time.Now(), &err)
+	return c.client.SuggestGasTipCap(ctx)
+}
+
+func (c *instrumentedClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (_ uint64, err error) {
+	defer c.observe("eth_estimateGas", time.Now(), &err)
+	return c.client.EstimateGas(ctx, call)
+}
+
+func (c *instrumentedClient) SendTransaction(ctx context.Context, tx *types.Transaction) (err error) {
+	defer c.observe("eth_sendRawTransaction", time.Now(), &err)
+	return c.client.SendTransaction(ctx, tx)
+}
+
+func (c *instrumentedClient) BalanceAt(ctx context.Context, address common.Address, block *big.Int) (_ *big.Int, err error) {
+	defer c.observe("eth_getBalance", time.Now(), &err)
+	return c.client.BalanceAt(ctx, address, block)
+}
+
+func (c *instrumentedClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (_ *types.Receipt, err error) {
+	defer c.observe("eth_getTransactionReceipt", time.Now(), &err)
+	return c.client.TransactionReceipt(ctx, txHash)
+}
+
+func (c *instrumentedClient) TransactionByHash(ctx context.Context, txHash common.Hash) (_ *types.Transaction, _ bool, err error) {
+	defer c.observe("eth_getTransactionByHash", time.Now(), &err)
+	return c.client.TransactionByHash(ctx, txHash)
+}
+
+func (c *instrumentedClient) BlockNumber(ctx context.Context) (_ uint64, err error) {
+	defer c.observe("eth_blockNumber", time.Now(), &err)
+	return c.client.BlockNumber(ctx)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	walletmock "github.com/ethersphere/node-funder/pkg/wallet/mock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MetricsRPCCallErrors(t *testing.T) {
+	t.Parallel()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	errSend := errors.New("connection reset")
+
+	fail := true
+	client := walletmock.NewBackendClient(
+		walletmock.WithReceiptOutcome(walletmock.ReceiptPending),
+		walletmock.WithSendTransaction(func(*types.Transaction) error {
+			if fail {
+				fail = false
+				return errSend
+			}
+
+			return nil
+		}),
+	)
+
+	m := wallet.NewMetrics()
+	w := wallet.New(client, key,
+		wallet.WithMetricsOption(m),
+		wallet.WithReceiptTimeoutOption(20*time.Millisecond),
+		wallet.WithPollIntervalOption(5*time.Millisecond),
+	)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	_, err = w.TransferNative(context.Background(), to, big.NewInt(1))
+	assert.ErrorIs(t, err, errSend)
+
+	_, err = w.TransferNative(context.Background(), to, big.NewInt(1))
+	assert.ErrorIs(t, err, wallet.ErrTransactionPending)
+
+	// receipts of transactions waiting to be mined are not found, which is
+	// not an error
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RPCCallErrors.WithLabelValues("eth_sendRawTransaction")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.RPCCallErrors.WithLabelValues("eth_getTransactionReceipt")))
+}
+
+// closingClient records whether it was closed.
+type closingClient struct {
+	wallet.BackendClient
+	closed bool
+}
+
+func (c *closingClient) Close() {
+	c.closed = true
+}
+
+func Test_MetricsClose(t *testing.T) {
+	t.Parallel()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	client := &closingClient{BackendClient: walletmock.NewBackendClient()}
+
+	w := wallet.New(client, key, wallet.WithMetricsOption(wallet.NewMetrics()))
+	w.Close()
+
+	assert.True(t, client.closed)
+}
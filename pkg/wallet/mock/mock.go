@@ -7,7 +7,9 @@ package mock
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,23 +17,109 @@ import (
 	"github.com/ethersphere/node-funder/pkg/wallet"
 )
 
-func NewBackendClient() wallet.BackendClient {
-	return &client{}
+const (
+	mockBlockNumber   = 100
+	mockReceiptNumber = 90
+)
+
+// ReceiptOutcome determines how transactions sent to the mock end up.
+type ReceiptOutcome int
+
+const (
+	ReceiptMined ReceiptOutcome = iota
+	ReceiptReverted
+	ReceiptPending
+)
+
+type Option func(*client)
+
+// WithReceiptOutcome sets the outcome of all transactions sent to the client.
+func WithReceiptOutcome(outcome ReceiptOutcome) Option {
+	return func(c *client) {
+		c.receiptOutcome = outcome
+	}
+}
+
+// WithBalance sets native coin and swarm token balances of the address,
+// other addresses hold the default balances.
+func WithBalance(addr common.Address, native, swarm *big.Int) Option {
+	return func(c *client) {
+		c.nativeBalances[addr] = native
+		c.swarmBalances[addr] = swarm
+	}
+}
+
+// WithPendingNonce sets pending nonce of all addresses, it is incremented by
+// every transaction sent successfully.
+func WithPendingNonce(nonce uint64) Option {
+	return func(c *client) {
+		c.pendingNonce = nonce
+	}
 }
 
-type client struct{}
+// WithSendTransaction sets function called with every sent transaction, its
+// error is returned by SendTransaction.
+func WithSendTransaction(f func(*types.Transaction) error) Option {
+	return func(c *client) {
+		c.sendTransaction = f
+	}
+}
+
+// WithTransactionReceipt sets function called with hash of every transaction
+// whose receipt is queried.
+func WithTransactionReceipt(f func(common.Hash)) Option {
+	return func(c *client) {
+		c.transactionReceipt = f
+	}
+}
+
+func NewBackendClient(opts ...Option) wallet.BackendClient {
+	c := &client{
+		nativeBalances: make(map[common.Address]*big.Int),
+		swarmBalances:  make(map[common.Address]*big.Int),
+		sent:           make(map[common.Hash]*types.Transaction),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type client struct {
+	receiptOutcome  ReceiptOutcome
+	nativeBalances  map[common.Address]*big.Int
+	swarmBalances   map[common.Address]*big.Int
+	sendTransaction func(*types.Transaction) error
+	// transactionReceipt is called with every queried receipt
+	transactionReceipt func(common.Hash)
+
+	mu           sync.Mutex
+	pendingNonce uint64
+	sent         map[common.Hash]*types.Transaction
+}
 
 func (c *client) ChainID(ctx context.Context) (*big.Int, error) {
 	return big.NewInt(100), nil
 }
 
-func (c *client) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+func (c *client) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	// balanceOf(address) call data holds the address in the last 32 bytes
+	if len(call.Data) == 36 {
+		if balance, ok := c.swarmBalances[common.BytesToAddress(call.Data[4:])]; ok {
+			return common.LeftPadBytes(balance.Bytes(), 32), nil
+		}
+	}
+
 	// balanceOf 2.0574776217600000 xBZZ
 	return hex.DecodeString("000000000000000000000000000000000000000000000000004918a663c88000")
 }
 
 func (c *client) PendingNonceAt(context.Context, common.Address) (uint64, error) {
-	return 0, nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pendingNonce, nil
 }
 
 func (c *client) SuggestGasPrice(context.Context) (*big.Int, error) {
@@ -42,15 +130,79 @@ func (c *client) SuggestGasTipCap(context.Context) (*big.Int, error) {
 	return big.NewInt(10), nil
 }
 
-func (c *client) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+func (c *client) EstimateGas(_ context.Context, call ethereum.CallMsg) (uint64, error) {
+	// transfer(address,uint256) call data holds the amount in the last 32
+	// bytes, the transfer reverts when the sender's balance does not cover it
+	if len(call.Data) == 68 {
+		balance, ok := c.swarmBalances[call.From]
+		if ok && new(big.Int).SetBytes(call.Data[36:]).Cmp(balance) > 0 {
+			return 0, errors.New("execution reverted: ERC20: transfer amount exceeds balance")
+		}
+	}
+
 	return 10, nil
 }
 
-func (c *client) SendTransaction(context.Context, *types.Transaction) error {
+func (c *client) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if c.sendTransaction != nil {
+		if err := c.sendTransaction(tx); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pendingNonce++
+	c.sent[tx.Hash()] = tx
+
 	return nil
 }
 
-func (c *client) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+func (c *client) BalanceAt(_ context.Context, addr common.Address, _ *big.Int) (*big.Int, error) {
+	if balance, ok := c.nativeBalances[addr]; ok {
+		return balance, nil
+	}
+
 	// 1 xDAI
 	return big.NewInt(1000000000000000000), nil
 }
+
+func (c *client) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if c.transactionReceipt != nil {
+		c.transactionReceipt(txHash)
+	}
+
+	status := types.ReceiptStatusSuccessful
+
+	switch c.receiptOutcome {
+	case ReceiptPending:
+		return nil, ethereum.NotFound
+	case ReceiptReverted:
+		status = types.ReceiptStatusFailed
+	}
+
+	return &types.Receipt{
+		TxHash:      txHash,
+		Status:      status,
+		BlockNumber: big.NewInt(mockReceiptNumber),
+	}, nil
+}
+
+// TransactionByHash returns transactions sent to the client, they are pending
+// with ReceiptPending outcome.
+func (c *client) TransactionByHash(_ context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, ok := c.sent[txHash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+
+	return tx, c.receiptOutcome == ReceiptPending, nil
+}
+
+func (c *client) BlockNumber(context.Context) (uint64, error) {
+	return mockBlockNumber, nil
+}
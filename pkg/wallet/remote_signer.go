@@ -0,0 +1,181 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// SignMethodEth is JSON-RPC method of nodes and signers exposing the eth
+	// namespace, e.g. web3signer.
+	SignMethodEth = "eth_signTransaction"
+	// SignMethodClef is JSON-RPC method of Clef external API.
+	SignMethodClef = "account_signTransaction"
+)
+
+// ErrRemoteSignerMismatch is returned when transaction signed by the remote
+// signer is not the requested transaction signed by the wallet address.
+var ErrRemoteSignerMismatch = errors.New("remote signer returned unexpected transaction")
+
+// RemoteSigner signs transactions with a signer running in a separate process,
+// so the private key is never in memory of the funder.
+type RemoteSigner struct {
+	client  *rpc.Client
+	method  string
+	address common.Address
+}
+
+var _ Signer = (*RemoteSigner)(nil)
+
+// NewRemoteSigner returns signer of transactions sent from the address by the
+// JSON-RPC endpoint with the method, SignMethodEth or SignMethodClef. When the
+// address is zero, the only account of the signer is used.
+func NewRemoteSigner(ctx context.Context, endpoint, method string, address common.Address) (*RemoteSigner, error) {
+	if method != SignMethodEth && method != SignMethodClef {
+		return nil, fmt.Errorf("unsupported sign method %q", method)
+	}
+
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dial remote signer: %w", err)
+	}
+
+	s := &RemoteSigner{
+		client:  client,
+		method:  method,
+		address: address,
+	}
+
+	if address == (common.Address{}) {
+		if s.address, err = s.account(ctx); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// account returns the only account of the signer.
+func (s *RemoteSigner) account(ctx context.Context) (common.Address, error) {
+	method := "eth_accounts"
+	if s.method == SignMethodClef {
+		method = "account_list"
+	}
+
+	var accounts []common.Address
+	if err := s.client.CallContext(ctx, &accounts, method); err != nil {
+		return common.Address{}, fmt.Errorf("list remote signer accounts: %w", err)
+	}
+
+	if len(accounts) != 1 {
+		return common.Address{}, fmt.Errorf("remote signer has %d accounts, wallet address must be set", len(accounts))
+	}
+
+	return accounts[0], nil
+}
+
+// Close closes connection to the signer.
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+func (s *RemoteSigner) PublicAddress() (common.Address, error) {
+	return s.address, nil
+}
+
+// signTxArgs are arguments of the sign transaction methods.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Input                hexutil.Bytes   `json:"input"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// SignTx sends the transaction to the signer and verifies it is signed
+// unchanged by the wallet address.
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:                 s.address,
+		To:                   tx.To(),
+		Gas:                  hexutil.Uint64(tx.Gas()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		Value:                (*hexutil.Big)(tx.Value()),
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Data:                 tx.Data(),
+		Input:                tx.Data(),
+		ChainID:              (*hexutil.Big)(chainID),
+	}
+
+	var result json.RawMessage
+	if err := s.client.CallContext(ctx, &result, s.method, args); err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+
+	raw, err := decodeSignTxResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("decode signed transaction: %w", err)
+	}
+
+	txSigner := types.NewLondonSigner(chainID)
+	if txSigner.Hash(signed) != txSigner.Hash(tx) {
+		return nil, ErrRemoteSignerMismatch
+	}
+
+	from, err := types.Sender(txSigner, signed)
+	if err != nil {
+		return nil, fmt.Errorf("recover signer: %w", err)
+	}
+
+	if from != s.address {
+		return nil, fmt.Errorf("%w: signed by %s", ErrRemoteSignerMismatch, from)
+	}
+
+	return signed, nil
+}
+
+// decodeSignTxResult returns raw signed transaction of the result, which is
+// either an object with raw field (geth, Clef) or the raw transaction itself.
+func decodeSignTxResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var obj struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &obj); err != nil {
+		return nil, fmt.Errorf("decode remote signer result: %w", err)
+	}
+
+	if len(obj.Raw) == 0 {
+		return nil, errors.New("remote signer result has no raw transaction")
+	}
+
+	return obj.Raw, nil
+}
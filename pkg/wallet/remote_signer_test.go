@@ -0,0 +1,163 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet_test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	walletmock "github.com/ethersphere/node-funder/pkg/wallet/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// signerService is stand-in of a remote signer, serving both eth and Clef
+// (account) namespaces.
+type signerService struct {
+	key    wallet.Key
+	tamper bool // signs a different transaction than requested
+}
+
+func (s *signerService) Accounts() ([]common.Address, error) {
+	address, err := s.key.PublicAddress()
+	return []common.Address{address}, err
+}
+
+func (s *signerService) List() ([]common.Address, error) {
+	return s.Accounts()
+}
+
+func (s *signerService) SignTransaction(ctx context.Context, args wallet.SignTxArgs) (map[string]hexutil.Bytes, error) {
+	nonce := uint64(args.Nonce)
+	if s.tamper {
+		nonce++
+	}
+
+	chainID := args.ChainID.ToInt()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Input,
+	})
+
+	signed, err := s.key.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]hexutil.Bytes{"raw": raw}, nil
+}
+
+func newSignerServer(t *testing.T, service *signerService) string {
+	t.Helper()
+
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	assert.NoError(t, server.RegisterName("account", service))
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(func() {
+		ts.Close()
+		server.Stop()
+	})
+
+	return ts.URL
+}
+
+func newRemoteSigner(t *testing.T, endpoint, method string, address common.Address) *wallet.RemoteSigner {
+	t.Helper()
+
+	signer, err := wallet.NewRemoteSigner(context.Background(), endpoint, method, address)
+	assert.NoError(t, err)
+	t.Cleanup(signer.Close)
+
+	return signer
+}
+
+func Test_RemoteSigner(t *testing.T) {
+	t.Parallel()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+	address, err := key.PublicAddress()
+	assert.NoError(t, err)
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	for _, method := range []string{wallet.SignMethodEth, wallet.SignMethodClef} {
+		t.Run(method, func(t *testing.T) {
+			t.Parallel()
+
+			signer := newRemoteSigner(t, newSignerServer(t, &signerService{key: key}), method, common.Address{})
+
+			got, err := signer.PublicAddress()
+			assert.NoError(t, err)
+			assert.Equal(t, address, got)
+
+			var sent []*types.Transaction
+
+			client := walletmock.NewBackendClient(walletmock.WithSendTransaction(func(tx *types.Transaction) error {
+				sent = append(sent, tx)
+				return nil
+			}))
+			w := wallet.New(client, signer)
+
+			txHash, err := w.TransferNative(context.Background(), to, big.NewInt(1))
+			assert.NoError(t, err)
+			assert.Equal(t, sent[0].Hash(), txHash)
+
+			txHash, err = w.TransferERC20(context.Background(), to, big.NewInt(1), wallet.Token{Contract: to, Decimals: 16})
+			assert.NoError(t, err)
+			assert.Equal(t, sent[1].Hash(), txHash)
+
+			for _, tx := range sent {
+				from, err := types.Sender(types.NewLondonSigner(tx.ChainId()), tx)
+				assert.NoError(t, err)
+				assert.Equal(t, address, from)
+			}
+		})
+	}
+
+	t.Run("tampered transaction", func(t *testing.T) {
+		t.Parallel()
+
+		signer := newRemoteSigner(t, newSignerServer(t, &signerService{key: key, tamper: true}), wallet.SignMethodEth, address)
+
+		_, err := wallet.New(walletmock.NewBackendClient(), signer).TransferNative(context.Background(), to, big.NewInt(1))
+		assert.ErrorIs(t, err, wallet.ErrRemoteSignerMismatch)
+	})
+
+	t.Run("other address", func(t *testing.T) {
+		t.Parallel()
+
+		signer := newRemoteSigner(t, newSignerServer(t, &signerService{key: key}), wallet.SignMethodEth, to)
+
+		_, err := wallet.New(walletmock.NewBackendClient(), signer).TransferNative(context.Background(), to, big.NewInt(1))
+		assert.ErrorIs(t, err, wallet.ErrRemoteSignerMismatch)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := wallet.NewRemoteSigner(context.Background(), "http://localhost", "personal_sign", address)
+		assert.ErrorContains(t, err, "unsupported sign method")
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	btcececdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Signer signs transactions of the wallet, so the private key can be kept in
+// memory of the funder (Key) or in a separate process (RemoteSigner).
+type Signer interface {
+	// PublicAddress returns address transactions are sent from.
+	PublicAddress() (common.Address, error)
+	// SignTx returns the transaction signed for the chain.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+var _ Signer = Key("")
+
+// SignTx signs hash of the transaction with the key.
+func (k Key) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txSigner := types.NewLondonSigner(chainID)
+	hash := txSigner.Hash(tx).Bytes()
+
+	signature, err := k.sign(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// v value needs to be adjusted by 27 as transaction.WithSignature expects it to be 0 or 1
+	signature[64] -= 27
+
+	return tx.WithSignature(txSigner, signature)
+}
+
+// sign the provided hash and convert it to the ethereum (r,s,v) format.
+func (k Key) sign(sighash []byte) ([]byte, error) {
+	privateECDSA, err := k.PrivateECDSA()
+	if err != nil {
+		return nil, err
+	}
+
+	pvk, _ := btcec.PrivKeyFromBytes(privateECDSA.D.Bytes())
+
+	// isCompressedKey is false here so we get the expected v value (27 or 28)
+	signature, err := btcececdsa.SignCompact(pvk, sighash, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to Ethereum signature format with 'recovery id' v at the end.
+	v := signature[0]
+	copy(signature, signature[1:])
+	signature[64] = v
+
+	return signature, nil
+}
@@ -12,6 +12,8 @@ import (
 
 const (
 	SwarmTokenDecimals = 16
+	SwarmTokenBaseUnit = "plur"
+	NativeCoinBaseUnit = "wei"
 	LocalnetChainID    = 12345
 )
 
@@ -19,6 +21,7 @@ type Token struct {
 	Contract common.Address
 	Symbol   string
 	Decimals int
+	BaseUnit string // name of the smallest token unit
 }
 
 var chainToSwarmTokenMap = map[int64]Token{
@@ -27,6 +30,7 @@ var chainToSwarmTokenMap = map[int64]Token{
 		Contract: common.HexToAddress("0x543dDb01Ba47acB11de34891cD86B675F04840db"),
 		Symbol:   "sBZZ",
 		Decimals: SwarmTokenDecimals,
+		BaseUnit: SwarmTokenBaseUnit,
 	},
 
 	// Gnosis Mainnet
@@ -34,6 +38,7 @@ var chainToSwarmTokenMap = map[int64]Token{
 		Contract: common.HexToAddress("0xdBF3Ea6F5beE45c02255B2c26a16F300502F68da"),
 		Symbol:   "xBZZ",
 		Decimals: SwarmTokenDecimals,
+		BaseUnit: SwarmTokenBaseUnit,
 	},
 
 	// Localnet
@@ -41,6 +46,7 @@ var chainToSwarmTokenMap = map[int64]Token{
 		Contract: common.HexToAddress("0x6aab14fe9cccd64a502d23842d916eb5321c26e7"),
 		Symbol:   "tBZZ",
 		Decimals: SwarmTokenDecimals,
+		BaseUnit: SwarmTokenBaseUnit,
 	},
 }
 
@@ -49,18 +55,21 @@ var chainToNativeCoinMap = map[int64]Token{
 	11155111: {
 		Symbol:   "sETH",
 		Decimals: 18,
+		BaseUnit: NativeCoinBaseUnit,
 	},
 
 	// Mainnet
 	100: {
 		Symbol:   "xDAI",
 		Decimals: 18,
+		BaseUnit: NativeCoinBaseUnit,
 	},
 
 	// Localnet
 	LocalnetChainID: {
 		Symbol:   "tETH",
 		Decimals: 18,
+		BaseUnit: NativeCoinBaseUnit,
 	},
 }
 
@@ -6,17 +6,15 @@ package wallet
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
-	"github.com/btcsuite/btcd/btcec/v2"
-	btcececdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 )
 
 const (
@@ -24,26 +22,51 @@ const (
 	txSendMaxRetries    = 3
 )
 
+var (
+	ErrTransactionReverted = errors.New("transaction reverted")
+	ErrTransactionPending  = errors.New("transaction not mined")
+)
+
 type TransactionSender interface {
 	Send(
 		ctx context.Context,
 		toAddr common.Address,
 		amount *big.Int,
 		callData []byte,
-	) error
+	) (common.Hash, error)
+
+	WaitForReceipt(
+		ctx context.Context,
+		txHash common.Hash,
+	) (*types.Receipt, error)
+
+	EstimateFee(
+		ctx context.Context,
+		toAddr common.Address,
+		amount *big.Int,
+		callData []byte,
+	) (*big.Int, error)
 }
 
 type transactionSender struct {
-	client    BackendClient
-	key       Key
-	nonceLock sync.Mutex
-	nonceLast uint64
+	client         BackendClient
+	signer         Signer
+	confirmations  uint64
+	receiptTimeout time.Duration
+	pollInterval   time.Duration
+	metrics        *Metrics
+	sendLock       sync.Mutex // serializes nonce allocation and submission
+	nonceLast      uint64
 }
 
-func newTransactionSender(client BackendClient, key Key) TransactionSender {
+func newTransactionSender(client BackendClient, signer Signer, opts *Options) TransactionSender {
 	return &transactionSender{
-		client: client,
-		key:    key,
+		client:         client,
+		signer:         signer,
+		confirmations:  opts.confirmations,
+		receiptTimeout: opts.receiptTimeout,
+		pollInterval:   opts.pollInterval,
+		metrics:        opts.metrics,
 	}
 }
 
@@ -52,30 +75,113 @@ func (s *transactionSender) Send(
 	toAddr common.Address,
 	amount *big.Int,
 	callData []byte,
-) error {
+) (common.Hash, error) {
 	chainID, err := s.client.ChainID(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get network id, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to get network id, %w", err)
 	}
 
-	_, publicKey, err := s.keys()
+	fromAddress, err := s.signer.PublicAddress()
 	if err != nil {
-		return fmt.Errorf("failed to get wallet keys, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to get wallet address, %w", err)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKey)
+	var txHash common.Hash
 
 	for i := 0; i < txSendMaxRetries; i++ {
-		err = s.send(ctx, chainID, toAddr, fromAddress, amount, callData)
+		// nonce of the failed send is already cleared
+		txHash, err = s.send(ctx, chainID, toAddr, fromAddress, amount, callData)
 		if err != nil && err.Error() == "replacement transaction underpriced" {
-			s.clearNonce()
 			continue
 		}
 
-		return err
+		return txHash, err
+	}
+
+	return txHash, err
+}
+
+// EstimateFee returns the maximum fee (in native coin) the transaction
+// would cost when sent with the current gas price suggestions.
+func (s *transactionSender) EstimateFee(
+	ctx context.Context,
+	toAddr common.Address,
+	_ *big.Int,
+	callData []byte,
+) (*big.Int, error) {
+	fromAddress, err := s.signer.PublicAddress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet address, %w", err)
+	}
+
+	gas, gasFeeCap, _, err := s.calculateGas(ctx, ethereum.CallMsg{
+		From: fromAddress,
+		To:   &toAddr,
+		Data: callData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas, %w", err)
+	}
+
+	return new(big.Int).Mul(new(big.Int).SetUint64(gas), gasFeeCap), nil
+}
+
+// WaitForReceipt polls for the receipt of the transaction until it is mined
+// and has the configured number of confirmations. It returns
+// ErrTransactionReverted when the transaction was mined but failed, and
+// ErrTransactionPending when it was not mined within the receipt timeout.
+func (s *transactionSender) WaitForReceipt(
+	ctx context.Context,
+	txHash common.Hash,
+) (*types.Receipt, error) {
+	timeout := time.NewTimer(s.receiptTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := s.client.TransactionReceipt(ctx, txHash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get transaction receipt, %w", err)
+		}
+
+		if receipt != nil {
+			confirmed, err := s.confirmed(ctx, receipt)
+			if err != nil {
+				return nil, err
+			}
+
+			if confirmed {
+				if receipt.Status != types.ReceiptStatusSuccessful {
+					return receipt, fmt.Errorf("%w (tx %s)", ErrTransactionReverted, txHash)
+				}
+
+				return receipt, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout.C:
+			return nil, fmt.Errorf("%w within %s (tx %s)", ErrTransactionPending, s.receiptTimeout, txHash)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *transactionSender) confirmed(ctx context.Context, receipt *types.Receipt) (bool, error) {
+	if s.confirmations == 0 {
+		return true, nil
 	}
 
-	return err
+	head, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get block number, %w", err)
+	}
+
+	return head >= receipt.BlockNumber.Uint64()+s.confirmations, nil
 }
 
 func (s *transactionSender) send(
@@ -85,19 +191,32 @@ func (s *transactionSender) send(
 	fromAddr common.Address,
 	amount *big.Int,
 	callData []byte,
-) error {
+) (_ common.Hash, err error) {
+	// the nonce is handed out and submitted before the next one, so a
+	// failed send never leaves a gap behind nonces held by other sends
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+
 	nonce, err := s.nonce(ctx, fromAddr)
 	if err != nil {
-		return fmt.Errorf("failed to make nonce, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to make nonce, %w", err)
 	}
 
+	// nonce of a transaction which was not sent is reused, as later
+	// transactions would wait behind the gap forever otherwise
+	defer func() {
+		if err != nil {
+			s.clearNonce()
+		}
+	}()
+
 	gas, gasFeeCap, gasTipCap, err := s.calculateGas(ctx, ethereum.CallMsg{
 		From: fromAddr,
 		To:   &toAddr,
 		Data: callData,
 	})
 	if err != nil {
-		return err
+		return common.Hash{}, err
 	}
 
 	tx := types.NewTx(&types.DynamicFeeTx{
@@ -111,17 +230,17 @@ func (s *transactionSender) send(
 		Data:      callData,
 	})
 
-	signedTx, err := s.signTx(tx, chainID)
+	signedTx, err := s.signer.SignTx(ctx, tx, chainID)
 	if err != nil {
-		return fmt.Errorf("failed to sign transaction, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to sign transaction, %w", err)
 	}
 
 	err = s.client.SendTransaction(ctx, signedTx)
 	if err != nil {
-		return fmt.Errorf("failed to send transaction, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to send transaction, %w", err)
 	}
 
-	return nil
+	return signedTx.Hash(), nil
 }
 
 func (s *transactionSender) calculateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, *big.Int, *big.Int, error) {
@@ -160,48 +279,8 @@ func (s *transactionSender) suggestedFeeAndTip(ctx context.Context, boostPercent
 	return gasFeeCap, gasTipCap, nil
 }
 
-func (s *transactionSender) signTx(transaction *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	txSigner := types.NewLondonSigner(chainID)
-	hash := txSigner.Hash(transaction).Bytes()
-
-	// isCompressedKey is false here so we get the expected v value (27 or 28)
-	signature, err := s.sign(hash, false)
-	if err != nil {
-		return nil, err
-	}
-
-	// v value needs to be adjusted by 27 as transaction.WithSignature expects it to be 0 or 1
-	signature[64] -= 27
-
-	return transaction.WithSignature(txSigner, signature)
-}
-
-// sign the provided hash and convert it to the ethereum (r,s,v) format.
-func (s *transactionSender) sign(sighash []byte, _ bool) ([]byte, error) {
-	privateECDSA, err := s.key.PrivateECDSA()
-	if err != nil {
-		return nil, err
-	}
-
-	pvk, _ := btcec.PrivKeyFromBytes(privateECDSA.D.Bytes())
-
-	signature, err := btcececdsa.SignCompact(pvk, sighash, false)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert to Ethereum signature format with 'recovery id' v at the end.
-	v := signature[0]
-	copy(signature, signature[1:])
-	signature[64] = v
-
-	return signature, nil
-}
-
+// nonce returns nonce of the next transaction, it is called with sendLock held.
 func (s *transactionSender) nonce(ctx context.Context, addr common.Address) (uint64, error) {
-	s.nonceLock.Lock()
-	defer s.nonceLock.Unlock()
-
 	if s.nonceLast == 0 {
 		nonce, err := s.client.PendingNonceAt(ctx, addr)
 		if err != nil {
@@ -216,23 +295,9 @@ func (s *transactionSender) nonce(ctx context.Context, addr common.Address) (uin
 	return s.nonceLast, nil
 }
 
+// clearNonce makes the next transaction use the pending nonce of the chain
+// node, it is called with sendLock held.
 func (s *transactionSender) clearNonce() {
-	s.nonceLock.Lock()
-	defer s.nonceLock.Unlock()
-
 	s.nonceLast = 0
-}
-
-func (s *transactionSender) keys() (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
-	privateKey, err := s.key.PrivateECDSA()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	publicKeyECDSA, err := s.key.PublicECDSA()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return privateKey, publicKeyECDSA, nil
+	s.metrics.nonceReset()
 }
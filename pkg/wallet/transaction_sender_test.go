@@ -0,0 +1,113 @@
+// Copyright 2026 The Swarm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wallet_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethersphere/node-funder/pkg/wallet"
+	walletmock "github.com/ethersphere/node-funder/pkg/wallet/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TransferReusesNonceOfFailedSend(t *testing.T) {
+	t.Parallel()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	errSend := errors.New("connection reset")
+
+	var nonces []uint64
+
+	fail := true
+	client := walletmock.NewBackendClient(
+		walletmock.WithPendingNonce(5),
+		walletmock.WithSendTransaction(func(tx *types.Transaction) error {
+			nonces = append(nonces, tx.Nonce())
+
+			if fail {
+				fail = false
+				return errSend
+			}
+
+			return nil
+		}),
+	)
+
+	w := wallet.New(client, key)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	_, err = w.TransferNative(context.Background(), to, big.NewInt(1))
+	assert.ErrorIs(t, err, errSend)
+
+	_, err = w.TransferNative(context.Background(), to, big.NewInt(1))
+	assert.NoError(t, err)
+
+	_, err = w.TransferNative(context.Background(), to, big.NewInt(1))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []uint64{5, 5, 6}, nonces)
+}
+
+func Test_ConcurrentTransfersUseUniqueNonces(t *testing.T) {
+	t.Parallel()
+
+	key, err := wallet.GenerateKey()
+	assert.NoError(t, err)
+
+	errSend := errors.New("connection reset")
+
+	var (
+		mu     sync.Mutex
+		calls  int
+		nonces []uint64
+	)
+
+	client := walletmock.NewBackendClient(
+		walletmock.WithPendingNonce(5),
+		walletmock.WithSendTransaction(func(tx *types.Transaction) error {
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls++
+			if calls == 3 {
+				return errSend
+			}
+
+			nonces = append(nonces, tx.Nonce())
+
+			return nil
+		}),
+	)
+
+	w := wallet.New(client, key)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, _ = w.TransferNative(context.Background(), to, big.NewInt(1))
+		}()
+	}
+	wg.Wait()
+
+	// nonce of the failed send is reused, no nonce is used twice
+	slices.Sort(nonces)
+	assert.Equal(t, []uint64{5, 6, 7, 8, 9, 10, 11, 12, 13}, nonces)
+}
@@ -7,9 +7,12 @@ package wallet
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -19,6 +22,81 @@ import (
 
 var erc20ABI = mustParseABI(sw3abi.ERC20ABIv0_6_5)
 
+const (
+	defaultReceiptTimeout = 5 * time.Minute
+	defaultPollInterval   = 2 * time.Second
+)
+
+type WalletOptions func(*Options)
+
+// Options represents wallet options
+type Options struct {
+	confirmations  uint64
+	receiptTimeout time.Duration
+	pollInterval   time.Duration
+	metrics        *Metrics
+}
+
+// DefaultOptions returns default options
+func DefaultOptions() *Options {
+	return &Options{
+		receiptTimeout: defaultReceiptTimeout,
+		pollInterval:   defaultPollInterval,
+	}
+}
+
+// WithConfirmationsOption sets the number of blocks that have to be mined on
+// top of the transaction's block before the transfer is considered done
+func WithConfirmationsOption(confirmations uint64) WalletOptions {
+	return func(o *Options) {
+		o.confirmations = confirmations
+	}
+}
+
+// WithReceiptTimeoutOption sets how long to wait for the transaction receipt
+func WithReceiptTimeoutOption(timeout time.Duration) WalletOptions {
+	return func(o *Options) {
+		if timeout > 0 {
+			o.receiptTimeout = timeout
+		}
+	}
+}
+
+// WithPollIntervalOption sets how often the transaction receipt is polled
+func WithPollIntervalOption(interval time.Duration) WalletOptions {
+	return func(o *Options) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+// WithMetricsOption sets metrics the wallet updates, nil means no metrics
+func WithMetricsOption(m *Metrics) WalletOptions {
+	return func(o *Options) {
+		o.metrics = m
+	}
+}
+
+type receiptContextKey struct{}
+
+// WithReceiptContext returns copy of ctx with which transfers are sent, while
+// their receipts are awaited with receiptCtx, e.g. so transfers already sent
+// are still awaited for a while after ctx is canceled on shutdown.
+func WithReceiptContext(ctx, receiptCtx context.Context) context.Context {
+	return context.WithValue(ctx, receiptContextKey{}, receiptCtx)
+}
+
+// receiptContext returns context receipts of transfers sent with ctx are
+// awaited with.
+func receiptContext(ctx context.Context) context.Context {
+	if receiptCtx, ok := ctx.Value(receiptContextKey{}).(context.Context); ok {
+		return receiptCtx
+	}
+
+	return ctx
+}
+
 type TokenWallet interface {
 	Balance(
 		ctx context.Context,
@@ -26,44 +104,115 @@ type TokenWallet interface {
 		token Token,
 	) (*big.Int, error)
 
+	// Transfer sends the amount to the address and waits until the
+	// transaction is mined. The returned hash is set whenever the
+	// transaction was sent, even if it reverted or was not mined in time.
+	// The receipt is awaited with context set by WithReceiptContext, if any.
 	Transfer(
 		ctx context.Context,
 		toAddr common.Address,
 		amount *big.Int,
 		token Token,
-	) error
+	) (common.Hash, error)
+
+	// TransferFee estimates the fee (in native coin) of the transfer.
+	TransferFee(
+		ctx context.Context,
+		toAddr common.Address,
+		amount *big.Int,
+		token Token,
+	) (*big.Int, error)
 }
 
 type Wallet struct {
-	key    Key
+	signer Signer
 	client BackendClient
 	native TokenWallet
 	erc20  TokenWallet
+
+	chainIDMu sync.Mutex
+	chainID   int64 // cached, zero until fetched
 }
 
-func New(client BackendClient, key Key) *Wallet {
-	trxSender := newTransactionSender(client, key)
+// New returns wallet sending transactions signed by the signer, e.g. Key.
+func New(client BackendClient, signer Signer, options ...WalletOptions) *Wallet {
+	opts := DefaultOptions()
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.metrics != nil {
+		client = newInstrumentedClient(client, opts.metrics)
+	}
+
+	trxSender := newTransactionSender(client, signer, opts)
 
 	return &Wallet{
-		key:    key,
+		signer: signer,
 		client: client,
 		native: newNativeWallet(client, trxSender),
 		erc20:  newERC20Wallet(client, trxSender),
 	}
 }
 
+// Close closes connections of the wallet to the chain node and the remote
+// signer, when they support closing.
+func (w *Wallet) Close() {
+	for _, v := range []any{w.signer, w.client} {
+		if c, ok := v.(interface{ Close() }); ok {
+			c.Close()
+		}
+	}
+}
+
 func (w *Wallet) PublicAddress() common.Address {
-	addr, _ := w.key.PublicAddress()
+	addr, _ := w.signer.PublicAddress()
 	return addr
 }
 
+// ChainID returns chain ID of the chain node. It is fetched once and cached
+// for the lifetime of the wallet.
 func (w *Wallet) ChainID(ctx context.Context) (int64, error) {
+	w.chainIDMu.Lock()
+	defer w.chainIDMu.Unlock()
+
+	if w.chainID != 0 {
+		return w.chainID, nil
+	}
+
 	id, err := w.client.ChainID(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get network id, %w", err)
 	}
 
-	return id.Int64(), nil
+	w.chainID = id.Int64()
+
+	return w.chainID, nil
+}
+
+// TransactionPending reports whether the transaction is known to the chain
+// node and still waiting to be mined. It is false once the transaction is
+// mined, or when the node dropped it.
+func (w *Wallet) TransactionPending(ctx context.Context, txHash common.Hash) (bool, error) {
+	receipt, err := w.client.TransactionReceipt(ctx, txHash)
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return false, fmt.Errorf("failed to get transaction receipt, %w", err)
+	}
+
+	if receipt != nil {
+		return false, nil
+	}
+
+	_, pending, err := w.client.TransactionByHash(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to get transaction, %w", err)
+	}
+
+	return pending, nil
 }
 
 func (w *Wallet) Native() TokenWallet {
@@ -85,7 +234,7 @@ func (w *Wallet) TransferNative(
 	ctx context.Context,
 	toAddr common.Address,
 	amount *big.Int,
-) error {
+) (common.Hash, error) {
 	return w.native.Transfer(ctx, toAddr, amount, Token{})
 }
 
@@ -102,7 +251,7 @@ func (w *Wallet) TransferERC20(
 	toAddr common.Address,
 	amount *big.Int,
 	token Token,
-) error {
+) (common.Hash, error) {
 	return w.erc20.Transfer(ctx, toAddr, amount, token)
 }
 
@@ -126,13 +275,26 @@ func (w *nativeWallet) Transfer(
 	toAddr common.Address,
 	amount *big.Int,
 	token Token,
-) error {
-	err := w.trxSender.Send(ctx, toAddr, amount, nil)
+) (common.Hash, error) {
+	txHash, err := w.trxSender.Send(ctx, toAddr, amount, nil)
 	if err != nil {
-		return fmt.Errorf("failed to make native token transfer, %w", err)
+		return common.Hash{}, fmt.Errorf("failed to make native token transfer, %w", err)
 	}
 
-	return nil
+	if _, err = w.trxSender.WaitForReceipt(receiptContext(ctx), txHash); err != nil {
+		return txHash, fmt.Errorf("native token transfer not completed, %w", err)
+	}
+
+	return txHash, nil
+}
+
+func (w *nativeWallet) TransferFee(
+	ctx context.Context,
+	toAddr common.Address,
+	amount *big.Int,
+	token Token,
+) (*big.Int, error) {
+	return w.trxSender.EstimateFee(ctx, toAddr, amount, nil)
 }
 
 func (w *nativeWallet) Balance(
@@ -194,32 +356,64 @@ func (w *erc20Wallet) Transfer(
 	toAddr common.Address,
 	amount *big.Int,
 	token Token,
-) error {
+) (common.Hash, error) {
+	callData, err := w.transferCallData(ctx, toAddr, amount)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	txHash, err := w.trxSender.Send(ctx, token.Contract, nil, callData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to make ERC20 token transfer, %w", err)
+	}
+
+	if _, err = w.trxSender.WaitForReceipt(receiptContext(ctx), txHash); err != nil {
+		return txHash, fmt.Errorf("ERC20 token transfer not completed, %w", err)
+	}
+
+	return txHash, nil
+}
+
+func (w *erc20Wallet) TransferFee(
+	ctx context.Context,
+	toAddr common.Address,
+	amount *big.Int,
+	token Token,
+) (*big.Int, error) {
+	callData, err := w.transferCallData(ctx, toAddr, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.trxSender.EstimateFee(ctx, token.Contract, nil, callData)
+}
+
+func (w *erc20Wallet) transferCallData(
+	ctx context.Context,
+	toAddr common.Address,
+	amount *big.Int,
+) ([]byte, error) {
 	chainID, err := w.client.ChainID(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get network id, %w", err)
+		return nil, fmt.Errorf("failed to get network id, %w", err)
 	}
 
 	callData, err := erc20ABI.Pack("transfer", toAddr, amount)
 	if err != nil {
-		return fmt.Errorf("failed to pack abi, %w", err)
+		return nil, fmt.Errorf("failed to pack abi, %w", err)
 	}
 
 	// Custom handling for LocalnetChainID.
 	if chainID.Int64() == LocalnetChainID {
 		mint, decodeErr := hex.DecodeString("40c10f19") // mint(address,uint256)
 		if decodeErr != nil {
-			return fmt.Errorf("failed decode string %w", err)
+			return nil, fmt.Errorf("failed decode string %w", err)
 		}
 		// Replace the first 4 bytes of the call data (transfer) with the localnet mint function.
 		copy(callData[:4], mint)
 	}
 
-	if err = w.trxSender.Send(ctx, token.Contract, nil, callData); err != nil {
-		return fmt.Errorf("failed to make ERC20 token transfer, %w", err)
-	}
-
-	return nil
+	return callData, nil
 }
 
 func mustParseABI(json string) abi.ABI {